@@ -4,23 +4,27 @@ import "encoding/json"
 
 // Attachment represents a base Discord attachment
 type Attachment struct {
-	ID          string `json:"id"`
-	Filename    string `json:"filename"`
-	Description string `json:"description"`
-	ContentType string `json:"content_type"`
-	Size        int    `json:"size"`
-	URL         string `json:"url"`
-	ProxyURL    string `json:"proxy_url"`
-	Width       int    `json:"width"`
-	Height      int    `json:"height"`
-	Ephemeral   bool   `json:"ephemeral"`
+	ID          Snowflake `json:"id"`
+	Filename    string    `json:"filename"`
+	Description string    `json:"description"`
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	URL         string    `json:"url"`
+	ProxyURL    string    `json:"proxy_url"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Ephemeral   bool      `json:"ephemeral"`
+	Duration    float64   `json:"duration_secs"` // voice messages only
+	Waveform    string    `json:"waveform"`      // voice messages only
+	Title       string    `json:"title"`
+	Flags       int       `json:"flags"`
 }
 
 // PartialAttachment represents a partial Discord attachment
 type PartialAttachment struct {
-	Id          string `json:"id"`
-	Filename    string `json:"filename"`
-	Description string `json:"description"`
+	Id          Snowflake `json:"id"`
+	Filename    string    `json:"filename"`
+	Description string    `json:"description"`
 }
 
 // UnmarshalAttachment unmarshals a payload into an Attachment.
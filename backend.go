@@ -0,0 +1,243 @@
+package disgo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// CacheBackend stores the guilds of a State and, apart from them, their channels, roles
+// and members, so single entities are read and written without loading a whole guild;
+// guilds are passed without their Roles, Members and Channels, deleting a guild deletes
+// its entities. The State serializes writes, reads may be called concurrently with each other
+type CacheBackend interface {
+	Load(id Snowflake) (*Guild, bool)
+	Store(guild *Guild)
+	Delete(id Snowflake)
+	Range(fn func(guild *Guild) bool)
+
+	Channel(guildId Snowflake, id Snowflake) (Channel, bool)
+	Channels(guildId Snowflake) []Channel
+	StoreChannel(channel Channel)
+	DeleteChannel(guildId Snowflake, id Snowflake)
+
+	Role(guildId Snowflake, id Snowflake) (Role, bool)
+	Roles(guildId Snowflake) []Role
+	StoreRole(role Role)
+	DeleteRole(guildId Snowflake, id Snowflake)
+
+	Member(guildId Snowflake, userId Snowflake) (Member, bool)
+	Members(guildId Snowflake) []Member
+	StoreMember(member Member)
+	DeleteMember(guildId Snowflake, userId Snowflake)
+
+	Close() error
+}
+
+// MemoryCache is the default backend, keeping guilds in process memory
+type MemoryCache struct {
+	guilds map[Snowflake]*Guild // along with their entities
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{guilds: map[Snowflake]*Guild{}}
+}
+
+// bare copies the guild without its entities
+func bare(guild *Guild) *Guild {
+	c := *guild
+	c.Roles, c.Members, c.Channels = nil, nil, nil
+	return &c
+}
+
+func (c *MemoryCache) Load(id Snowflake) (*Guild, bool) {
+	g, ok := c.guilds[id]
+	if !ok {
+		return nil, false
+	}
+	return bare(g), true
+}
+
+func (c *MemoryCache) Store(guild *Guild) {
+	g := bare(guild)
+	if old, ok := c.guilds[guild.Id]; ok {
+		g.Roles, g.Members, g.Channels = old.Roles, old.Members, old.Channels
+	} else {
+		g.Roles, g.Members, g.Channels = map[Snowflake]Role{}, map[Snowflake]Member{}, map[Snowflake]Channel{}
+	}
+	c.guilds[guild.Id] = g
+}
+
+func (c *MemoryCache) Delete(id Snowflake) {
+	delete(c.guilds, id)
+}
+
+func (c *MemoryCache) Range(fn func(guild *Guild) bool) {
+	for _, g := range c.guilds {
+		if !fn(bare(g)) {
+			return
+		}
+	}
+}
+
+func (c *MemoryCache) Channel(guildId Snowflake, id Snowflake) (Channel, bool) {
+	if g, ok := c.guilds[guildId]; ok {
+		ch, ok := g.Channels[id]
+		return ch, ok
+	}
+	return Channel{}, false
+}
+
+func (c *MemoryCache) Channels(guildId Snowflake) []Channel {
+	var channels []Channel
+	if g, ok := c.guilds[guildId]; ok {
+		for _, ch := range g.Channels {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+func (c *MemoryCache) StoreChannel(channel Channel) {
+	if g, ok := c.guilds[channel.GuildId]; ok {
+		g.Channels[channel.Id] = channel
+	}
+}
+
+func (c *MemoryCache) DeleteChannel(guildId Snowflake, id Snowflake) {
+	if g, ok := c.guilds[guildId]; ok {
+		delete(g.Channels, id)
+	}
+}
+
+func (c *MemoryCache) Role(guildId Snowflake, id Snowflake) (Role, bool) {
+	if g, ok := c.guilds[guildId]; ok {
+		r, ok := g.Roles[id]
+		return r, ok
+	}
+	return Role{}, false
+}
+
+func (c *MemoryCache) Roles(guildId Snowflake) []Role {
+	var roles []Role
+	if g, ok := c.guilds[guildId]; ok {
+		for _, r := range g.Roles {
+			roles = append(roles, r)
+		}
+	}
+	return roles
+}
+
+func (c *MemoryCache) StoreRole(role Role) {
+	if g, ok := c.guilds[role.GuildId]; ok {
+		g.Roles[role.Id] = role
+	}
+}
+
+func (c *MemoryCache) DeleteRole(guildId Snowflake, id Snowflake) {
+	if g, ok := c.guilds[guildId]; ok {
+		delete(g.Roles, id)
+	}
+}
+
+func (c *MemoryCache) Member(guildId Snowflake, userId Snowflake) (Member, bool) {
+	if g, ok := c.guilds[guildId]; ok {
+		m, ok := g.Members[userId]
+		return m, ok
+	}
+	return Member{}, false
+}
+
+func (c *MemoryCache) Members(guildId Snowflake) []Member {
+	var members []Member
+	if g, ok := c.guilds[guildId]; ok {
+		for _, m := range g.Members {
+			members = append(members, m)
+		}
+	}
+	return members
+}
+
+func (c *MemoryCache) StoreMember(member Member) {
+	if g, ok := c.guilds[member.GuildId]; ok {
+		g.Members[member.User.Id] = member
+	}
+}
+
+func (c *MemoryCache) DeleteMember(guildId Snowflake, userId Snowflake) {
+	if g, ok := c.guilds[guildId]; ok {
+		delete(g.Members, userId)
+	}
+}
+
+func (c *MemoryCache) Close() error {
+	return nil
+}
+
+// FileCache keeps guilds in memory and snapshots them to a
+// json file on Close, restoring the snapshot when created
+type FileCache struct {
+	*MemoryCache
+	Path string
+}
+
+// NewFileCache creates a file backend, loading the snapshot at path if one exists;
+// restored guilds are reconciled with the fresh payloads once the bot connects
+func NewFileCache(path string) (*FileCache, error) {
+	c := &FileCache{MemoryCache: NewMemoryCache(), Path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var guilds []*Guild
+	if err = json.Unmarshal(data, &guilds); err != nil {
+		return nil, err
+	}
+	for _, g := range guilds {
+		if g.Roles == nil {
+			g.Roles = map[Snowflake]Role{}
+		}
+		if g.Members == nil {
+			g.Members = map[Snowflake]Member{}
+		}
+		if g.Channels == nil {
+			g.Channels = map[Snowflake]Channel{}
+		}
+		c.guilds[g.Id] = g
+	}
+	return c, nil
+}
+
+// Save writes the snapshot, replacing the previous one atomically
+func (c *FileCache) Save() error {
+	guilds := make([]*Guild, 0, len(c.guilds))
+	for _, g := range c.guilds {
+		guilds = append(guilds, g)
+	}
+	data, err := json.Marshal(guilds)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
+
+func (c *FileCache) Close() error {
+	return c.Save()
+}
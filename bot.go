@@ -2,23 +2,27 @@ package disgo
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 )
 
 type BotUser struct {
-	Id            string `json:"id"`
-	Username      string `json:"username"`
-	Discriminator string `json:"discriminator"`
-	Avatar        string `json:"avatar"`
-	MfaEnabled    bool   `json:"mfa_enabled"`
-	Banner        string `json:"banner"`
-	Color         int    `json:"accent_color"`
-	Locale        string `json:"locale"`
-	Verified      bool   `json:"verified"`
-	Flags         int    `json:"flags"`
-	PublicFlags   int    `json:"public_flags"`
-	Latency       int64  `json:"latency"`
+	Id            Snowflake `json:"id"`
+	Username      string    `json:"username"`
+	Discriminator string    `json:"discriminator"`
+	Avatar        string    `json:"avatar"`
+	MfaEnabled    bool      `json:"mfa_enabled"`
+	Banner        string    `json:"banner"`
+	Color         int       `json:"accent_color"`
+	Locale        string    `json:"locale"`
+	Verified      bool      `json:"verified"`
+	Flags         int       `json:"flags"`
+	PublicFlags   int       `json:"public_flags"`
+	Latency       int64     `json:"latency"`
 	IsReady       bool
-	Guilds        map[string]*Guild
+	State         *State `json:"-"`
+	token         string
+	applicationId Snowflake
 }
 
 func Unmarshal(payload interface{}) *BotUser {
@@ -27,3 +31,59 @@ func Unmarshal(payload interface{}) *BotUser {
 	_ = json.Unmarshal(data, bot)
 	return bot
 }
+
+// fetch sends a request and decodes the response into v,
+// a non 2xx response is returned as an error carrying the message of discord
+func (bot *BotUser) fetch(method string, path string, data map[string]interface{}, token string, v interface{}) error {
+	resp, err := MinimalReq(method, path, data, token).Do()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &e)
+		return fmt.Errorf("%s %s failed with status %d: %s (code %d)", method, path, resp.StatusCode, e.Message, e.Code)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// FetchCommandPermissions returns the overrides of a command in a guild
+func (bot *BotUser) FetchCommandPermissions(guildId Snowflake, commandId Snowflake) (*GuildCommandPermissions, error) {
+	perms := &GuildCommandPermissions{}
+	path := fmt.Sprintf("/applications/%s/guilds/%s/commands/%s/permissions", bot.applicationId, guildId, commandId)
+	if err := bot.fetch("GET", path, nil, bot.token, perms); err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+// FetchGuildCommandPermissions returns the overrides of every command in a guild
+func (bot *BotUser) FetchGuildCommandPermissions(guildId Snowflake) ([]GuildCommandPermissions, error) {
+	var perms []GuildCommandPermissions
+	path := fmt.Sprintf("/applications/%s/guilds/%s/commands/permissions", bot.applicationId, guildId)
+	if err := bot.fetch("GET", path, nil, bot.token, &perms); err != nil {
+		return nil, err
+	}
+	return perms, nil
+}
+
+// EditCommandPermissions overwrites the overrides of a command in a guild,
+// discord only accepts an OAuth2 bearer token with the applications.commands.permissions.update scope
+func (bot *BotUser) EditCommandPermissions(
+	bearer string, guildId Snowflake, commandId Snowflake, perms ...CommandPermission) (*GuildCommandPermissions, error) {
+	updated := &GuildCommandPermissions{}
+	path := fmt.Sprintf("/applications/%s/guilds/%s/commands/%s/permissions", bot.applicationId, guildId, commandId)
+	body := map[string]interface{}{"permissions": perms}
+	if err := bot.fetch("PUT", path, body, fmt.Sprintf("Bearer %s", bearer), updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,65 @@
+package disgo
+
+const (
+	CacheGuilds = 1 << iota
+	CacheChannels
+	CacheRoles
+	CacheMembers
+	CacheMessages
+	CachePresences
+	CacheVoiceStates
+	CacheEmojis
+	CacheStickers
+)
+
+const CacheAll = CacheGuilds | CacheChannels | CacheRoles | CacheMembers | CacheMessages |
+	CachePresences | CacheVoiceStates | CacheEmojis | CacheStickers
+
+// CachePolicy decides which entities the State keeps and for how long
+type CachePolicy struct {
+	Flags        int                               // default: CacheAll
+	MemberFilter func(event string, m Member) bool // default: cache every member
+	MaxMembers   int                               // max members across all guilds, default: unbounded
+	MemberTTL    float64                           // seconds a member is kept since last seen, default: forever
+	MaxMessages  int                               // max messages across all channels, default: 1000
+	MessageTTL   float64                           // seconds a message is kept, default: forever
+}
+
+const guildEntities = CacheChannels | CacheRoles | CacheMembers |
+	CachePresences | CacheVoiceStates | CacheEmojis | CacheStickers
+
+// has reports whether every entity of the flags is cached,
+// guild entities are only cached along with their guild
+func (p *CachePolicy) has(flags int) bool {
+	f := p.Flags
+	if f == 0 {
+		f = CacheAll
+	}
+	if flags&guildEntities != 0 && f&CacheGuilds == 0 {
+		return false
+	}
+	return f&flags == flags
+}
+
+// MemberFilterVoice caches members only once they are seen in a voice channel
+func MemberFilterVoice(event string, m Member) bool {
+	return event == "VOICE_STATE_UPDATE"
+}
+
+// MemberFilterActive caches members only once they send a message,
+// combine it with MemberTTL to keep recently active members
+func MemberFilterActive(event string, m Member) bool {
+	return event == "MESSAGE_CREATE"
+}
+
+// AnyMemberFilter caches a member when one of the filters accepts it
+func AnyMemberFilter(filters ...func(event string, m Member) bool) func(event string, m Member) bool {
+	return func(event string, m Member) bool {
+		for _, f := range filters {
+			if f(event, m) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,144 @@
+package disgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// Catalog is a source of localized messages used by Context.T
+type Catalog interface {
+	// Lookup returns the plural forms of a message for the given locale
+	Lookup(locale string, key string) (Translation, bool)
+	// DefaultLocale is used when neither the user nor the guild locale has the message
+	DefaultLocale() string
+}
+
+// Translation holds the plural forms of a message keyed by the CLDR
+// categories "zero", "one", "two", "few", "many" and "other"
+type Translation map[string]string
+
+func (t *Translation) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Translation{"other": s}
+		return nil
+	}
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	*t = forms
+	return nil
+}
+
+var pluralForms = map[plural.Form]string{
+	plural.Zero: "zero",
+	plural.One:  "one",
+	plural.Two:  "two",
+	plural.Few:  "few",
+	plural.Many: "many",
+}
+
+// Format picks the plural form for the first numeric argument using the CLDR rules
+// of the locale, and replaces positional placeholders like {0}, {1} with args;
+// an explicit "zero" form is used for 0 even in locales without that category
+func (t Translation) Format(locale string, args ...interface{}) string {
+	form := "other"
+	for _, arg := range args {
+		if n, ok := pluralCount(arg); ok {
+			if n == 0 && t["zero"] != "" {
+				form = "zero"
+			} else if f, ok := pluralForms[pluralForm(locale, n)]; ok && t[f] != "" {
+				form = f
+			}
+			break
+		}
+	}
+	msg := t[form]
+	if msg == "" {
+		msg = t["other"]
+	}
+	for i, arg := range args {
+		msg = strings.ReplaceAll(msg, fmt.Sprintf("{%d}", i), fmt.Sprint(arg))
+	}
+	return msg
+}
+
+// pluralForm matches the cardinal plural category of n,
+// deriving the CLDR operands from its shortest decimal form
+func pluralForm(locale string, n float64) plural.Form {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return plural.Other
+	}
+	digits := strconv.FormatFloat(n, 'f', -1, 64)
+	digits = strings.TrimPrefix(digits, "-")
+	integer, fraction, _ := strings.Cut(digits, ".")
+	i, _ := strconv.Atoi(integer)
+	f, _ := strconv.Atoi(fraction)
+	trimmed := strings.TrimRight(fraction, "0")
+	w, _ := strconv.Atoi(trimmed)
+	return plural.Cardinal.MatchPlural(tag, i, len(fraction), len(trimmed), f, w)
+}
+
+func pluralCount(arg interface{}) (float64, bool) {
+	switch n := arg.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// JSONCatalog is a Catalog backed by one JSON file per locale
+type JSONCatalog struct {
+	Default  string
+	Messages map[string]map[string]Translation
+}
+
+// LoadCatalog reads every <locale>.json file in dir,
+// each file maps message keys to a string or an object of plural forms
+func LoadCatalog(dir string, defaultLocale string) (*JSONCatalog, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	c := &JSONCatalog{Default: defaultLocale, Messages: map[string]map[string]Translation{}}
+	for _, path := range paths {
+		bs, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]Translation
+		if err = json.Unmarshal(bs, &messages); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		c.Messages[strings.TrimSuffix(filepath.Base(path), ".json")] = messages
+	}
+	return c, nil
+}
+
+func (c *JSONCatalog) Lookup(locale string, key string) (Translation, bool) {
+	t, ok := c.Messages[locale][key]
+	return t, ok
+}
+
+func (c *JSONCatalog) DefaultLocale() string {
+	return c.Default
+}
@@ -2,35 +2,111 @@ package disgo
 
 import "encoding/json"
 
+type ChannelType int
+
+const (
+	GuildTextChannel          ChannelType = 0
+	DMChannel                 ChannelType = 1
+	GuildVoiceChannel         ChannelType = 2
+	GroupDMChannel            ChannelType = 3
+	GuildCategoryChannel      ChannelType = 4
+	GuildAnnouncementChannel  ChannelType = 5
+	AnnouncementThreadChannel ChannelType = 10
+	PublicThreadChannel       ChannelType = 11
+	PrivateThreadChannel      ChannelType = 12
+	GuildStageVoiceChannel    ChannelType = 13
+	GuildDirectoryChannel     ChannelType = 14
+	GuildForumChannel         ChannelType = 15
+	GuildMediaChannel         ChannelType = 16
+)
+
+func (t ChannelType) IsThread() bool {
+	return t == AnnouncementThreadChannel || t == PublicThreadChannel || t == PrivateThreadChannel
+}
+
+// IsText reports whether messages can be sent in the channel itself,
+// forum and media channels only accept messages in their threads
+func (t ChannelType) IsText() bool {
+	switch t {
+	case GuildTextChannel, DMChannel, GroupDMChannel, GuildAnnouncementChannel:
+		return true
+	}
+	return t.IsThread()
+}
+
+func (t ChannelType) IsVoice() bool {
+	return t == GuildVoiceChannel || t == GuildStageVoiceChannel
+}
+
 // Channel represents a Discord channel of any type
 type Channel struct {
-	Id                         string        `json:"id"`
-	Type                       int           `json:"type"`
-	GuildId                    string        `json:"guild_id"`
-	Position                   int           `json:"position"`
-	Overwrites                 []interface{} `json:"permission_overwrites"`
-	Name                       string        `json:"name"`
-	Topic                      string        `json:"topic"`
-	NSFW                       bool          `json:"nsfw"`
-	LastMessageId              string        `json:"last_message_id"`
-	Bitrate                    int           `json:"bitrate"`
-	UserLimit                  int           `json:"user_limit"`
-	RateLimitPerUser           int           `json:"rate_limit_per_user"`
-	Recipients                 []interface{} `json:"recipients"`
-	Icon                       string        `json:"icon"`
-	OwnerId                    string        `json:"owner_id"`
-	ApplicationId              string        `json:"application_id"`
-	ParentId                   string        `json:"parent_id"`
-	LastPinTime                int           `json:"last_pin_timestamp"`
-	RTCRegion                  string        `json:"rtc_region"`
-	VideoQualityMode           int           `json:"video_quality_mode"`
-	MessageCount               int           `json:"message_count"`
-	ThreadMetaData             interface{}   `json:"thread_metadata"`
-	Member                     interface{}   `json:"member"`
-	DefaultAutoArchiveDuration int           `json:"default_auto_archive_days"`
-	Permissions                string        `json:"permissions"`
-	Flags                      int           `json:"flags"`
-	TotalMessages              int           `json:"total_messages"`
+	Id                            Snowflake             `json:"id"`
+	Type                          ChannelType           `json:"type"`
+	GuildId                       Snowflake             `json:"guild_id"`
+	Position                      int                   `json:"position"`
+	Overwrites                    []PermissionOverwrite `json:"permission_overwrites"`
+	Name                          string                `json:"name"`
+	Topic                         string                `json:"topic"`
+	NSFW                          bool                  `json:"nsfw"`
+	LastMessageId                 Snowflake             `json:"last_message_id"`
+	Bitrate                       int                   `json:"bitrate"`
+	UserLimit                     int                   `json:"user_limit"`
+	RateLimitPerUser              int                   `json:"rate_limit_per_user"`
+	Recipients                    []User                `json:"recipients"`
+	Icon                          string                `json:"icon"`
+	OwnerId                       Snowflake             `json:"owner_id"`
+	ApplicationId                 Snowflake             `json:"application_id"`
+	ParentId                      Snowflake             `json:"parent_id"`
+	LastPinTime                   string                `json:"last_pin_timestamp"`
+	RTCRegion                     string                `json:"rtc_region"`
+	VideoQualityMode              int                   `json:"video_quality_mode"`
+	MessageCount                  int                   `json:"message_count"`
+	MemberCount                   int                   `json:"member_count"`
+	ThreadMetadata                *ThreadMetadata       `json:"thread_metadata"` // threads only
+	Member                        *ThreadMember         `json:"member"`          // threads the bot has joined only
+	DefaultAutoArchiveDuration    int                   `json:"default_auto_archive_duration"`
+	Permissions                   Permissions           `json:"permissions"`
+	Flags                         int                   `json:"flags"`
+	TotalMessages                 int                   `json:"total_message_sent"`
+	AvailableTags                 []ForumTag            `json:"available_tags"` // forum and media channels only
+	AppliedTags                   []Snowflake           `json:"applied_tags"`   // forum and media threads only
+	DefaultReactionEmoji          *DefaultReaction      `json:"default_reaction_emoji"`
+	DefaultThreadRateLimitPerUser int                   `json:"default_thread_rate_limit_per_user"`
+	DefaultSortOrder              *int                  `json:"default_sort_order"`   // nil (unset), 0 (latest activity), 1 (creation date)
+	DefaultForumLayout            int                   `json:"default_forum_layout"` // 0 (unset), 1 (list), 2 (gallery)
+}
+
+type ThreadMetadata struct {
+	Archived            bool   `json:"archived"`
+	AutoArchiveDuration int    `json:"auto_archive_duration"` // minutes: 60, 1440, 4320 or 10080
+	ArchiveTimestamp    string `json:"archive_timestamp"`
+	Locked              bool   `json:"locked"`
+	Invitable           bool   `json:"invitable"` // private threads only
+	CreateTimestamp     string `json:"create_timestamp"`
+}
+
+// ThreadMember is a user who joined a thread
+type ThreadMember struct {
+	Id            Snowflake `json:"id"` // thread id
+	UserId        Snowflake `json:"user_id"`
+	JoinTimestamp string    `json:"join_timestamp"`
+	Flags         int       `json:"flags"`
+	Member        *Member   `json:"member"`
+}
+
+// ForumTag is a tag which can be applied to threads of a forum or media channel
+type ForumTag struct {
+	Id        Snowflake `json:"id"`
+	Name      string    `json:"name"`
+	Moderated bool      `json:"moderated"` // only members with ManageThreadsPermission can apply it
+	EmojiId   Snowflake `json:"emoji_id"`
+	EmojiName string    `json:"emoji_name"`
+}
+
+// DefaultReaction is the emoji shown on the add reaction button of forum posts
+type DefaultReaction struct {
+	EmojiId   Snowflake `json:"emoji_id"`
+	EmojiName string    `json:"emoji_name"`
 }
 
 func UnmarshalChannel(payload interface{}) *Channel {
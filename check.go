@@ -1,5 +1,7 @@
 package disgo
 
+import "errors"
+
 func CheckTrueEmbed(em Embed) bool {
 	return em.Title != "" || em.Description != "" || len(em.Fields) > 0 || em.Author.IconUrl != "" || em.Author.Name != "" || em.Footer.IconUrl != "" || em.Footer.Text != "" || em.Image.Url != "" || em.Thumbnail.Url != ""
 }
@@ -7,3 +9,71 @@ func CheckTrueEmbed(em Embed) bool {
 func CheckTrueFile(f File) bool {
 	return f.Name != "" && len(f.Content) > 0
 }
+
+// Check decides whether a command or component callback may run,
+// the returned error is reported to the user through the failure responder
+type Check func(bot BotUser, ctx Context) error
+
+// GuildOnly allows invocations from guilds only
+func GuildOnly(bot BotUser, ctx Context) error {
+	if ctx.GuildId == "" {
+		return errors.New("This can only be used in a server")
+	}
+	return nil
+}
+
+// DMOnly allows invocations from direct messages only
+func DMOnly(bot BotUser, ctx Context) error {
+	if ctx.GuildId != "" {
+		return errors.New("This can only be used in direct messages")
+	}
+	return nil
+}
+
+// OwnerOnly allows invocations from the given user ids only
+func OwnerOnly(ids ...Snowflake) Check {
+	return func(bot BotUser, ctx Context) error {
+		for _, id := range ids {
+			if id == ctx.Author().Id {
+				return nil
+			}
+		}
+		return errors.New("Only the bot owner can use this")
+	}
+}
+
+// HasPermissions allows invocations from members having all the permissions in the channel
+func HasPermissions(perms Permissions) Check {
+	return func(bot BotUser, ctx Context) error {
+		if ctx.GuildId == "" {
+			return nil
+		}
+		if ctx.Member.Permissions.Has(AdministratorPermission) || ctx.Member.Permissions.Has(perms) {
+			return nil
+		}
+		return errors.New("You don't have the required permissions to use this")
+	}
+}
+
+type guard struct {
+	checks   []Check
+	cooldown *Cooldown
+}
+
+func (g guard) run(bot BotUser, ctx Context) error {
+	for _, check := range g.checks {
+		if err := check(bot, ctx); err != nil {
+			return err
+		}
+	}
+	if g.cooldown != nil {
+		if retry := g.cooldown.hit(ctx); retry > 0 {
+			return &CooldownError{RetryAfter: retry}
+		}
+	}
+	return nil
+}
+
+func defaultCheckFailure(bot BotUser, ctx Context, err error) {
+	ctx.Send(Response{Content: err.Error(), Ephemeral: true})
+}
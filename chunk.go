@@ -0,0 +1,95 @@
+package disgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MemberRequest selects the members fetched through the gateway,
+// either by username prefix or by ids
+type MemberRequest struct {
+	Query     string      // username prefix, "" with Limit 0 fetches every member
+	UserIds   []Snowflake // max 100 ids, replaces Query
+	Limit     int         // max 100, 0 fetches every member for an empty Query and 100 otherwise, ignored for UserIds
+	Presences bool        // requires the presences intent
+}
+
+type memberChunks struct {
+	guildId Snowflake
+	members []Member
+	done    chan struct{}
+}
+
+// RequestMembers fetches guild members through the gateway, waiting for
+// every chunk of the response; the members are also merged into the cache
+func (sock *Socket) RequestMembers(ctx context.Context, guildId Snowflake, req MemberRequest) ([]Member, error) {
+	if sock.conn == nil {
+		return nil, errors.New("gateway is not connected")
+	}
+	if len(req.UserIds) > 100 {
+		return nil, fmt.Errorf("member request can contain max 100 user ids, got %d", len(req.UserIds))
+	}
+	if req.Limit < 0 || req.Limit > 100 {
+		return nil, fmt.Errorf("member request limit must be between 0 and 100, got %d", req.Limit)
+	}
+	if req.Query != "" && req.Limit == 0 {
+		req.Limit = 100
+	}
+	nonce := AssignId("")
+	d := map[string]interface{}{"guild_id": guildId, "presences": req.Presences, "nonce": nonce}
+	if len(req.UserIds) > 0 {
+		d["user_ids"] = req.UserIds
+	} else {
+		d["query"] = req.Query
+		d["limit"] = req.Limit
+	}
+	pending := &memberChunks{guildId: guildId, done: make(chan struct{})}
+	sock.mu.Lock()
+	if sock.chunks == nil {
+		sock.chunks = map[string]*memberChunks{}
+	}
+	sock.chunks[nonce] = pending
+	sock.mu.Unlock()
+	defer func() {
+		sock.mu.Lock()
+		delete(sock.chunks, nonce)
+		sock.mu.Unlock()
+	}()
+	if err := sock.write(sock.conn, map[string]interface{}{"op": 8, "d": d}); err != nil {
+		return nil, err
+	}
+	select {
+	case <-pending.done:
+		return pending.members, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// collectChunk adds a GUILD_MEMBERS_CHUNK to the request of its nonce,
+// completing the request once the last chunk arrives
+func (sock *Socket) collectChunk(data map[string]interface{}) {
+	nonce, _ := data["nonce"].(string)
+	if nonce == "" {
+		return
+	}
+	sock.mu.Lock()
+	defer sock.mu.Unlock()
+	pending, ok := sock.chunks[nonce]
+	if !ok {
+		return
+	}
+	members, _ := data["members"].([]interface{})
+	for _, o := range members {
+		m := UnmarshalMember(o)
+		m.GuildId = pending.guildId
+		pending.members = append(pending.members, *m)
+	}
+	index, _ := data["chunk_index"].(float64)
+	count, _ := data["chunk_count"].(float64)
+	if index >= count-1 {
+		delete(sock.chunks, nonce)
+		close(pending.done)
+	}
+}
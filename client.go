@@ -1,5 +1,7 @@
 package disgo
 
+import "context"
+
 // Bot is a function that represents a connection to discord.
 func Bot(intent int, cache bool, presence Presence) *connection {
 	return &connection{sock: &Socket{Intent: intent, Memoize: cache, Presence: presence}}
@@ -9,6 +11,30 @@ type connection struct {
 	sock *Socket
 }
 
+// State returns the cache of guilds, channels, roles and members
+func (con *connection) State() *State {
+	if con.sock.state == nil {
+		con.sock.state = newState()
+	}
+	return con.sock.state
+}
+
+// SetCachePolicy sets which entities are cached and the bounds of the member cache
+func (con *connection) SetCachePolicy(policy CachePolicy) {
+	con.State().SetPolicy(policy)
+}
+
+// SetCacheBackend sets where the cache keeps guilds, e.g. a FileCache for warm restarts
+func (con *connection) SetCacheBackend(backend CacheBackend) {
+	con.State().SetBackend(backend)
+}
+
+// Close disconnects from the gateway and flushes the cache backend,
+// Run returns once the connection is closed
+func (con *connection) Close() error {
+	return con.sock.close()
+}
+
 func (con *connection) Run(token string) {
 	con.sock.Run(token)
 }
@@ -25,6 +51,19 @@ func (con *connection) OnMessage(handler func(bot BotUser, message Message)) {
 	con.sock.AddHandler(OnMessageCreate, handler)
 }
 
+// OnMessageUpdate receives the cached message before the edit, or nil when it was not cached
+func (con *connection) OnMessageUpdate(handler func(bot BotUser, before *Message, after Message)) {
+	con.sock.AddHandler(OnMessageUpdate, handler)
+}
+
+func (con *connection) OnMessageDelete(handler func(bot BotUser, message MessageDelete)) {
+	con.sock.AddHandler(OnMessageDelete, handler)
+}
+
+func (con *connection) OnMessageBulkDelete(handler func(bot BotUser, messages MessageBulkDelete)) {
+	con.sock.AddHandler(OnMessageDeleteBulk, handler)
+}
+
 func (con *connection) OnReady(handler func(bot BotUser)) {
 	con.sock.AddHandler(OnReady, handler)
 }
@@ -40,3 +79,47 @@ func (con *connection) OnGuildJoin(handler func(bot BotUser, guild Guild)) {
 func (con *connection) OnGuildLeave(handler func(bot BotUser, guild Guild)) {
 	con.sock.AddHandler(OnGuildDelete, handler)
 }
+
+func (con *connection) OnCommandPermissionsUpdate(handler func(bot BotUser, perms GuildCommandPermissions)) {
+	con.sock.AddHandler(OnAppCmdPermsUpdate, handler)
+}
+
+// SetCatalog sets the message catalog used by Context.T
+func (con *connection) SetCatalog(catalog Catalog) {
+	con.sock.Catalog = catalog
+}
+
+// OnCheckFailure sets the responder for rejected checks and cooldowns,
+// by default the error is sent as an ephemeral message
+func (con *connection) OnCheckFailure(handler func(bot BotUser, ctx Context, err error)) {
+	con.sock.CheckFailure = handler
+}
+
+// AddComponentHandler registers persistent handlers of components matched by custom id,
+// the captured parameters are passed to the handler and survive restarts
+func (con *connection) AddComponentHandler(handlers ...ComponentHandler) {
+	for _, h := range handlers {
+		con.sock.routes = append(con.sock.routes, newComponentRoute(h))
+	}
+}
+
+// SetCallbackStore replaces the in-memory store of component and modal callbacks,
+// it must be set before any component is marshalled
+func (con *connection) SetCallbackStore(store CallbackStore) {
+	con.sock.mu.Lock()
+	defer con.sock.mu.Unlock()
+	con.sock.Callbacks = store
+	con.sock.handlers = nil
+}
+
+// Callbacks returns the component and modal handlers of the connection,
+// pass them to Response.Marshal and the other marshallers used outside a Context
+func (con *connection) Callbacks() *Callbacks {
+	return con.sock.callbacks()
+}
+
+// RequestMembers fetches members of a guild through the gateway, by query or
+// user ids, returning once every chunk has arrived or the context is done
+func (con *connection) RequestMembers(ctx context.Context, guildId Snowflake, req MemberRequest) ([]Member, error) {
+	return con.sock.RequestMembers(ctx, guildId, req)
+}
@@ -1,40 +1,50 @@
 package disgo
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 type CommandOption struct {
-	Name         string          `json:"name"`
-	Description  string          `json:"description"`
-	Type         int             `json:"type"` // 3: string, 4: integer, 5: boolean, 6: user, 7: channel, 8: role, 9: mentionable, 10: number, 11: attachment
-	Required     bool            `json:"required,omitempty"`
-	MinLength    int             `json:"min_length,omitempty"`    // for type 3 only
-	MaxLength    int             `json:"max_length,omitempty"`    // for type 3 only
-	MinValue     int64           `json:"min_value,omitempty"`     // for type 4 and 10 only
-	MaxValue     int64           `json:"max_value,omitempty"`     // for type 4 and 10 only
-	AutoComplete bool            `json:"auto_complete,omitempty"` // for type 1 and
-	ChannelTypes []int           `json:"channel_types,omitempty"` // 0: guild text channel, 1: DM channel, 2: guild voice channel, 3: group DM channel, 4: guild category, 5: guild news, 10: guild news thread, 11: guild public thread, 12: guild private thread, 13: guild stage voice, 14: guild directory, 15: guild forum
-	Options      []CommandOption `json:"options,omitempty"`       // for type 1 and 2 only
-	Choices      []Choice        `json:"choices,omitempty"`       // for type 3 and 4 and 10 only
+	Name                     string            `json:"name"`
+	NameLocalizations        map[string]string `json:"name_localizations,omitempty"`
+	Description              string            `json:"description"`
+	DescriptionLocalizations map[string]string `json:"description_localizations,omitempty"`
+	Type                     int               `json:"type"` // 3: string, 4: integer, 5: boolean, 6: user, 7: channel, 8: role, 9: mentionable, 10: number, 11: attachment
+	Required                 bool              `json:"required,omitempty"`
+	MinLength                int               `json:"min_length,omitempty"`    // for type 3 only
+	MaxLength                int               `json:"max_length,omitempty"`    // for type 3 only
+	MinValue                 int64             `json:"min_value,omitempty"`     // for type 4 and 10 only
+	MaxValue                 int64             `json:"max_value,omitempty"`     // for type 4 and 10 only
+	AutoComplete             bool              `json:"auto_complete,omitempty"` // for type 1 and
+	ChannelTypes             []ChannelType     `json:"channel_types,omitempty"` // for type 7 only
+	Options                  []CommandOption   `json:"options,omitempty"`       // for type 1 and 2 only
+	Choices                  []Choice          `json:"choices,omitempty"`       // for type 3 and 4 and 10 only
 }
 
 // ApplicationCommand is a base type for all discord application commands
 type ApplicationCommand struct {
-	Type              int    // 1: slash command, 2: user command, 3: message command
-	Name              string // must be less than 32 characters
-	Description       string // must be less than 100 characters
-	Options           []CommandOption
-	DMPermission      bool // default: false
-	MemberPermissions int  // default: send_messages
-	GuildId           int64
-	Handler           func(bot BotUser, ctx Context, options ...SlashCommandOption)
+	Type                     int    // 1: slash command, 2: user command, 3: message command
+	Name                     string // must be less than 32 characters
+	NameLocalizations        map[string]string
+	Description              string // must be less than 100 characters
+	DescriptionLocalizations map[string]string
+	Options                  []CommandOption
+	DMPermission             *bool        // default: nil (allowed in DMs)
+	MemberPermissions        *Permissions // default: nil (everyone), 0 for admins only
+	NSFW                     bool
+	Contexts                 []int // 0: guild, 1: bot DM, 2: private channel
+	IntegrationTypes         []int // 0: guild install, 1: user install
+	GuildId                  Snowflake
+	Handler                  func(bot BotUser, ctx Context, options ...SlashCommandOption)
+	UserHandler              func(bot BotUser, ctx Context, user User, member Member) // for type 2 only
+	MessageHandler           func(bot BotUser, ctx Context, message Message)          // for type 3 only
+	Checks                   []Check
+	Cooldown                 *Cooldown
 }
 
-func (cmd *ApplicationCommand) Marshal() (
-	map[string]interface{},
-	func(bot BotUser, ctx Context, options ...SlashCommandOption),
-	int64) {
+func (cmd *ApplicationCommand) Marshal() (map[string]interface{}, interface{}, Snowflake) {
 	body := map[string]interface{}{}
 	switch cmd.Type {
 	case 3:
@@ -44,8 +54,22 @@ func (cmd *ApplicationCommand) Marshal() (
 	default:
 		body["type"] = 1
 	}
-	if cmd.Name == "" || cmd.Description == "" {
-		panic("Both command {name} or {description} must be set")
+	if cmd.Name == "" {
+		panic("Command {name} must be set")
+	}
+	if body["type"] == 1 && cmd.Description == "" {
+		panic(fmt.Sprintf("Command (%s) {description} must be set", cmd.Name))
+	}
+	if body["type"] != 1 && (cmd.Description != "" || len(cmd.Options) > 0) {
+		panic(fmt.Sprintf("Context menu command (%s) can not have {description} or {options}", cmd.Name))
+	}
+	switch {
+	case body["type"] == 1 && cmd.Handler == nil:
+		panic(fmt.Sprintf("Command (%s) {handler} must be set", cmd.Name))
+	case body["type"] == 2 && cmd.UserHandler == nil:
+		panic(fmt.Sprintf("User command (%s) {user handler} must be set", cmd.Name))
+	case body["type"] == 3 && cmd.MessageHandler == nil:
+		panic(fmt.Sprintf("Message command (%s) {message handler} must be set", cmd.Name))
 	}
 	if len(cmd.Name) > 32 {
 		panic(fmt.Sprintf("Command (%s) {name} must be less than 32 characters", cmd.Name))
@@ -54,21 +78,81 @@ func (cmd *ApplicationCommand) Marshal() (
 		panic(fmt.Sprintf("Command (%s) {description} must be less than 100 characters", cmd.Name))
 	}
 	body["name"] = cmd.Name
-	body["description"] = cmd.Description
-	body["dm_permission"] = cmd.DMPermission
-	switch cmd.MemberPermissions {
-	case 0:
-		body["default_member_permissions"] = 1 << 11
-	default:
-		body["default_member_permissions"] = cmd.MemberPermissions
+	if body["type"] == 1 {
+		body["description"] = cmd.Description
+	}
+	checkLocalizations(cmd.Name, "name", cmd.NameLocalizations, 32)
+	checkLocalizations(cmd.Name, "description", cmd.DescriptionLocalizations, 100)
+	if len(cmd.NameLocalizations) > 0 {
+		body["name_localizations"] = cmd.NameLocalizations
+	}
+	if len(cmd.DescriptionLocalizations) > 0 {
+		body["description_localizations"] = cmd.DescriptionLocalizations
+	}
+	if cmd.DMPermission != nil {
+		body["dm_permission"] = *cmd.DMPermission
+	}
+	if cmd.MemberPermissions != nil {
+		body["default_member_permissions"] = strconv.FormatInt(int64(*cmd.MemberPermissions), 10)
+	} else {
+		body["default_member_permissions"] = nil
+	}
+	if cmd.NSFW {
+		body["nsfw"] = true
+	}
+	if len(cmd.Contexts) > 0 {
+		body["contexts"] = cmd.Contexts
+	}
+	if len(cmd.IntegrationTypes) > 0 {
+		body["integration_types"] = cmd.IntegrationTypes
 	}
-	if cmd.Type == 1 {
-		body["options"] = cmd.Options
+	switch body["type"] {
+	case 2:
+		return body, cmd.UserHandler, cmd.GuildId
+	case 3:
+		return body, cmd.MessageHandler, cmd.GuildId
 	}
+	checkOptionLocalizations(cmd.Name, cmd.Options)
+	body["options"] = cmd.Options
 	return body, cmd.Handler, cmd.GuildId
 }
 
+func checkOptionLocalizations(command string, options []CommandOption) {
+	for _, op := range options {
+		owner := fmt.Sprintf("%s.%s", command, op.Name)
+		checkLocalizations(owner, "name", op.NameLocalizations, 32)
+		checkLocalizations(owner, "description", op.DescriptionLocalizations, 100)
+		for _, ch := range op.Choices {
+			checkLocalizations(fmt.Sprintf("%s.%s", owner, ch.Name), "name", ch.NameLocalizations, 100)
+		}
+		checkOptionLocalizations(owner, op.Options)
+	}
+}
+
 type Choice struct {
-	Name  string      `json:"name"`
-	Value interface{} `json:"value"` // same type as type of option
+	Name              string            `json:"name"`
+	NameLocalizations map[string]string `json:"name_localizations,omitempty"`
+	Value             interface{}       `json:"value"` // same type as type of option
+}
+
+// CommandPermission is a user, role or channel override of a command
+type CommandPermission struct {
+	Id         Snowflake `json:"id"`
+	Type       int       `json:"type"` // 1: role, 2: user, 3: channel
+	Permission bool      `json:"permission"`
+}
+
+// GuildCommandPermissions contains the overrides of a command in a guild
+type GuildCommandPermissions struct {
+	Id            Snowflake           `json:"id"`
+	ApplicationId Snowflake           `json:"application_id"`
+	GuildId       Snowflake           `json:"guild_id"`
+	Permissions   []CommandPermission `json:"permissions"`
+}
+
+func UnmarshalCommandPermissions(payload interface{}) *GuildCommandPermissions {
+	perms := &GuildCommandPermissions{}
+	data, _ := json.Marshal(payload)
+	_ = json.Unmarshal(data, perms)
+	return perms
 }
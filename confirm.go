@@ -0,0 +1,53 @@
+package disgo
+
+import "time"
+
+// Confirm is a prebuilt yes/no prompt answered by the user who invoked the interaction
+type Confirm struct {
+	Prompt       Response
+	ConfirmLabel string  // default: "Confirm"
+	CancelLabel  string  // default: "Cancel"
+	Timeout      float64 // default: 60 seconds
+}
+
+// Ask sends the prompt and blocks until the user answers,
+// ok is false when the prompt timed out without an answer
+func (c *Confirm) Ask(ctx Context) (confirmed bool, ok bool) {
+	if c.ConfirmLabel == "" {
+		c.ConfirmLabel = "Confirm"
+	}
+	if c.CancelLabel == "" {
+		c.CancelLabel = "Cancel"
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 60
+	}
+	answer := make(chan bool, 1)
+	view := NewView(c.Timeout)
+	view.AllowedUsers = []Snowflake{ctx.Author().Id}
+	view.DisableComponents = true
+	choose := func(choice bool) func(bot BotUser, ctx Context) {
+		return func(bot BotUser, ctx Context) {
+			select {
+			case answer <- choice:
+			default:
+			}
+			ctx.Defer(false)
+			view.Stop()
+		}
+	}
+	view.AddButtons(
+		Button{Style: 3, Label: c.ConfirmLabel, OnClick: choose(true)},
+		Button{Style: 4, Label: c.CancelLabel, OnClick: choose(false)},
+	)
+	prompt := c.Prompt
+	prompt.View = *view
+	ctx.Send(prompt)
+	select {
+	case confirmed = <-answer:
+		return confirmed, true
+	case <-time.After(time.Duration(c.Timeout * float64(time.Second))):
+		view.Stop()
+		return false, false
+	}
+}
@@ -3,6 +3,8 @@ package disgo
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 )
 
 type Component struct {
@@ -17,10 +19,11 @@ type Row struct {
 }
 
 type ComponentData struct {
-	ComponentType int      `json:"component_type"`
-	CustomId      string   `json:"custom_id"`
-	Values        []string `json:"values"`
-	Components    []Row    `json:"components"`
+	ComponentType int          `json:"component_type"`
+	CustomId      string       `json:"custom_id"`
+	Values        []string     `json:"values"`
+	Components    []Row        `json:"components"`
+	Resolved      ResolvedData `json:"resolved"`
 }
 
 type Response struct {
@@ -32,11 +35,17 @@ type Response struct {
 	Ephemeral       bool
 	SuppressEmbeds  bool
 	View            View
+	Layout          Layout // components v2, replaces content, embeds and view
 	File            File
 	Files           []File
 }
 
-func (resp *Response) Marshal() map[string]interface{} {
+// Marshal builds the message payload, registering the handlers of its components in callbacks
+func (resp *Response) Marshal(callbacks *Callbacks) map[string]interface{} {
+	return resp.marshal(callbacks)
+}
+
+func (resp *Response) marshal(callbacks *Callbacks) map[string]interface{} {
 	flag := 0
 	body := map[string]interface{}{}
 	if resp.Content != "" {
@@ -69,11 +78,19 @@ func (resp *Response) Marshal() map[string]interface{} {
 	if resp.SuppressEmbeds {
 		flag |= 1 << 2
 	}
-	if resp.Ephemeral || resp.SuppressEmbeds {
+	if len(resp.Layout.Components) > 0 {
+		if resp.Content != "" || len(resp.Embeds) > 0 || len(resp.View.ActionRows) > 0 {
+			panic("Response with a {layout} can not have {content}, {embeds} or a {view}")
+		}
+		flag |= 1 << 15
+		delete(body, "embeds")
+		body["components"] = resp.Layout.marshal(callbacks)
+	}
+	if flag != 0 {
 		body["flags"] = flag
 	}
 	if len(resp.View.ActionRows) > 0 {
-		body["components"] = resp.View.ToComponent()
+		body["components"] = resp.View.marshal(callbacks)
 	}
 	if CheckTrueFile(resp.File) {
 		resp.Files = append([]File{resp.File}, resp.Files...)
@@ -95,22 +112,27 @@ func (resp *Response) Marshal() map[string]interface{} {
 }
 
 type Context struct {
-	Id             string                 `json:"id"`
-	ApplicationId  string                 `json:"application_id"`
+	Id             Snowflake              `json:"id"`
+	ApplicationId  Snowflake              `json:"application_id"`
 	Type           int                    `json:"type"`
 	Data           InteractionData        `json:"data"`
-	GuildId        string                 `json:"guild_id"`
-	ChannelId      string                 `json:"channel_id"`
+	GuildId        Snowflake              `json:"guild_id"`
+	ChannelId      Snowflake              `json:"channel_id"`
 	Member         Member                 `json:"member"`
 	User           User                   `json:"user"`
 	Token          string                 `json:"token"`
 	Version        int                    `json:"version"`
 	Message        map[string]interface{} `json:"message"`
-	AppPermissions string                 `json:"app_permissions"`
+	AppPermissions Permissions            `json:"app_permissions"`
 	Locale         string                 `json:"locale"`
 	GuildLocale    string                 `json:"guild_locale"`
 	ComponentData  ComponentData          `json:"x_component"`
 	CommandData    []SlashCommandOption   `json:"x_command"`
+	catalog        Catalog
+	inline         *inlineResponder
+	handlers       *Callbacks
+	bot            *BotUser
+	view           *View
 }
 
 func UnmarshalContext(payload interface{}) *Context {
@@ -120,13 +142,97 @@ func UnmarshalContext(payload interface{}) *Context {
 	return c
 }
 
-func (c *Context) Send(resp Response) {
+// Author returns the user who invoked the interaction in a guild or DM
+func (c *Context) Author() User {
+	if c.Member.User.Id != "" {
+		return c.Member.User
+	}
+	return c.User
+}
+
+// T translates the message key using the user locale,
+// falling back to the guild locale and then the catalog default
+func (c *Context) T(key string, args ...interface{}) string {
+	if c.catalog == nil {
+		return key
+	}
+	for _, locale := range []string{c.Locale, c.GuildLocale, c.catalog.DefaultLocale()} {
+		if locale == "" {
+			continue
+		}
+		if t, ok := c.catalog.Lookup(locale, key); ok {
+			return t.Format(locale, args...)
+		}
+	}
+	return key
+}
+
+// TargetUser returns the user and member a user command was invoked on
+func (c *Context) TargetUser() (User, Member) {
+	r := c.Data.ResolvedData()
+	return r.Users[c.Data.TargetId], r.Members[c.Data.TargetId]
+}
+
+// TargetMessage returns the message a message command was invoked on
+func (c *Context) TargetMessage() Message {
+	return c.Data.ResolvedData().Messages[c.Data.TargetId]
+}
+
+func (c *Context) callbacks() *Callbacks {
+	return c.handlers
+}
+
+// callback sends the initial response of the interaction, either inline
+// in the HTTP response body or to the interaction callback endpoint
+func (c *Context) callback(body map[string]interface{}, files []File) {
+	if c.inline != nil && c.inline.respond(body, files) {
+		return
+	}
+	if ack := c.deferred(); ack != 0 {
+		c.afterDefer(ack, body, files)
+		return
+	}
 	path := fmt.Sprintf("/interactions/%s/%s/callback", c.Id, c.Token)
-	r := MultipartReq(
-		"POST", path, map[string]interface{}{"type": 4, "data": resp.Marshal()}, "", resp.Files)
+	r := MultipartReq("POST", path, body, "", files)
 	go r.Request()
 }
 
+// deferred returns the type of the acknowledgement sent on behalf
+// of a handler which missed the deadline of an HTTP interaction
+func (c *Context) deferred() int {
+	if c.inline == nil {
+		return 0
+	}
+	return c.inline.deferred()
+}
+
+// afterDefer delivers a response of a deferred interaction
+// by editing the original response instead
+func (c *Context) afterDefer(ack int, body map[string]interface{}, files []File) {
+	switch body["type"] {
+	case 4, 7:
+		data, _ := body["data"].(map[string]interface{})
+		path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", c.ApplicationId, c.Token)
+		r := MultipartReq("PATCH", path, data, "", files)
+		go r.Request()
+	case 5, 6:
+		// already acknowledged
+	default:
+		log.Println(fmt.Sprintf(
+			"Interaction response type (%v) can not be sent after the deferred acknowledgement (%d)", body["type"], ack))
+	}
+}
+
+func (c *Context) Send(resp Response) {
+	if c.deferred() == 6 {
+		// the message of the component is the original response, send a new one instead
+		c.SendFollowup(resp)
+		return
+	}
+	c.callback(map[string]interface{}{"type": 4, "data": resp.marshal(c.callbacks())}, resp.Files)
+	resp.View.track(*c, c.ChannelId, "", resp.Ephemeral)
+}
+
 func (c *Context) Defer(ephemeral bool) {
 	body := map[string]interface{}{}
 	if c.Type == 2 {
@@ -137,37 +243,88 @@ func (c *Context) Defer(ephemeral bool) {
 	} else {
 		body["type"] = 6
 	}
-	path := fmt.Sprintf("/interactions/%s/%s/callback", c.Id, c.Token)
-	r := MinimalReq("POST", path, body, "")
-	go r.Request()
+	c.callback(body, nil)
 }
 
 func (c *Context) SendModal(modal Modal) {
-	path := fmt.Sprintf("/interactions/%s/%s/callback", c.Id, c.Token)
-	r := MinimalReq("POST", path, modal.Marshal(), "")
-	go r.Request()
+	c.callback(modal.marshal(c.callbacks()), nil)
 }
 
 func (c *Context) SendFollowup(resp Response) {
 	path := fmt.Sprintf("/webhooks/%s/%s", c.ApplicationId, c.Token)
-	r := MultipartReq("POST", path, resp.Marshal(), "", resp.Files)
-	go r.Request()
-	// TODO: handle followup
+	r := MultipartReq("POST", path, resp.marshal(c.callbacks()), "", resp.Files)
+	go func() {
+		res := r.Request()
+		if res == nil || len(resp.View.ActionRows) == 0 {
+			return
+		}
+		defer res.Body.Close()
+		var msg struct {
+			Id        Snowflake `json:"id"`
+			ChannelId Snowflake `json:"channel_id"`
+		}
+		body, _ := io.ReadAll(res.Body)
+		_ = json.Unmarshal(body, &msg)
+		resp.View.track(*c, msg.ChannelId, msg.Id, resp.Ephemeral)
+	}()
 }
 
 func (c *Context) Edit(resp Response) {
 	if c.Type == 2 {
 		path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", c.ApplicationId, c.Token)
-		r := MultipartReq("PATCH", path, resp.Marshal(), "", resp.Files)
+		r := MultipartReq("PATCH", path, resp.marshal(c.callbacks()), "", resp.Files)
 		go r.Request()
+		resp.View.track(*c, c.ChannelId, "", false)
 	} else {
-		path := fmt.Sprintf("/interactions/%s/%s/callback", c.Id, c.Token)
-		body := map[string]interface{}{"type": 7, "data": resp.Marshal()}
-		r := MultipartReq("POST", path, body, "", resp.Files)
-		go r.Request()
+		c.callback(map[string]interface{}{"type": 7, "data": resp.marshal(c.callbacks())}, resp.Files)
+		resp.View.track(*c, c.ChannelId, snowflakeOf(c.Message["id"]), isEphemeral(c.Message))
 	}
 }
 
+// modalValues maps the custom ids of submitted modal components to their values
+func (c *Context) modalValues() map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, row := range c.ComponentData.Components {
+		for _, comp := range row.Components {
+			if comp.Type == 4 {
+				values[comp.CustomId] = comp.Value
+			} else {
+				values[comp.CustomId] = comp.Values
+			}
+		}
+	}
+	return values
+}
+
+// ModalValue returns the submitted value of a modal component by its custom id,
+// or the first chosen value for select menus
+func (c *Context) ModalValue(customId string) string {
+	switch v := c.modalValues()[customId].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// DecodeModal decodes the submitted modal values into the struct pointed to by v,
+// matching custom ids with the json tags of its fields
+func (c *Context) DecodeModal(v interface{}) error {
+	data, err := json.Marshal(c.modalValues())
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// View returns the view of the component that triggered the interaction
+func (c *Context) View() *View {
+	return c.view
+}
+
 func (c *Context) Delete() {
 	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", c.ApplicationId, c.Token)
 	r := MinimalReq("DELETE", path, nil, "")
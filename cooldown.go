@@ -0,0 +1,89 @@
+package disgo
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	UserBucket = iota
+	MemberBucket
+	ChannelBucket
+	GuildBucket
+	GlobalBucket
+)
+
+// Cooldown limits how often a command or component can be used per bucket
+type Cooldown struct {
+	Rate   int     // default: 1 use per window
+	Per    float64 // window in seconds
+	Bucket int     // default: UserBucket
+	mu     sync.Mutex
+	usage  map[string][]time.Time
+	swept  time.Time // last removal of idle buckets
+}
+
+// CooldownError is reported when a bucket is exhausted
+type CooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("You are on cooldown, try again in %.1fs", e.RetryAfter.Seconds())
+}
+
+func (cd *Cooldown) key(ctx Context) string {
+	switch cd.Bucket {
+	case MemberBucket:
+		return string(ctx.GuildId + ":" + ctx.Author().Id)
+	case ChannelBucket:
+		return string(ctx.ChannelId)
+	case GuildBucket:
+		if ctx.GuildId == "" {
+			return string(ctx.ChannelId)
+		}
+		return string(ctx.GuildId)
+	case GlobalBucket:
+		return ""
+	default:
+		return string(ctx.Author().Id)
+	}
+}
+
+// hit records a use and returns how long to wait if the bucket is exhausted
+func (cd *Cooldown) hit(ctx Context) time.Duration {
+	rate := cd.Rate
+	if rate <= 0 {
+		rate = 1
+	}
+	window := time.Duration(cd.Per * float64(time.Second))
+	now := time.Now()
+	cd.mu.Lock()
+	defer cd.mu.Unlock()
+	if cd.usage == nil {
+		cd.usage = map[string][]time.Time{}
+	}
+	if now.Sub(cd.swept) >= window {
+		// idle buckets are removed at most once per window
+		for k, uses := range cd.usage {
+			if len(uses) > 0 && now.Sub(uses[len(uses)-1]) >= window {
+				delete(cd.usage, k)
+			}
+		}
+		cd.swept = now
+	}
+	key := cd.key(ctx)
+	var uses []time.Time
+	for _, t := range cd.usage[key] {
+		if now.Sub(t) < window {
+			uses = append(uses, t)
+		}
+	}
+	if len(uses) >= rate {
+		cd.usage[key] = uses
+		return uses[0].Add(window).Sub(now)
+	}
+	cd.usage[key] = append(uses, now)
+	return 0
+}
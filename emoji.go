@@ -2,20 +2,21 @@ package disgo
 
 import "encoding/json"
 
+// PartialEmoji is a custom emoji by Id or a unicode emoji by Name
 type PartialEmoji struct {
-	Id       string `json:"id"`
-	Name     string `json:"name"`
-	Animated bool   `json:"animated,omitempty"`
+	Id       Snowflake `json:"id,omitempty"`
+	Name     string    `json:"name"`
+	Animated bool      `json:"animated,omitempty"`
 }
 
 type Emoji struct {
-	Id            int64    `json:"id,string"`
-	Name          string   `json:"name"`
-	Roles         []string `json:"roles"`
-	Managed       bool     `json:"managed"`
-	Animated      bool     `json:"animated"`
-	Available     bool     `json:"available"`
-	RequireColons bool     `json:"require_colons"`
+	Id            Snowflake   `json:"id"`
+	Name          string      `json:"name"`
+	Roles         []Snowflake `json:"roles"`
+	Managed       bool        `json:"managed"`
+	Animated      bool        `json:"animated"`
+	Available     bool        `json:"available"`
+	RequireColons bool        `json:"require_colons"`
 }
 
 func UnmarshalEmoji(payload interface{}) *Emoji {
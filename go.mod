@@ -3,3 +3,5 @@ module github.com/jnsougata/disgo
 go 1.18
 
 require github.com/gorilla/websocket v1.5.0
+
+require golang.org/x/text v0.14.0
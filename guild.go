@@ -5,79 +5,102 @@ import (
 )
 
 type Guild struct {
-	Id                          string                   `json:"id"`
-	Name                        string                   `json:"name"`
-	Icon                        string                   `json:"icon"`
-	IconHash                    string                   `json:"icon_hash"`
-	Splash                      string                   `json:"splash"`
-	DiscoverySplash             string                   `json:"discovery_splash"`
-	Owner                       bool                     `json:"owner"`
-	OwnerID                     string                   `json:"owner_id"`
-	Permissions                 string                   `json:"permissions"`
-	Region                      string                   `json:"region"`
-	AfkChannelID                string                   `json:"afk_channel_id"`
-	AfkTimeout                  int                      `json:"afk_timeout"`
-	WidgetEnabled               bool                     `json:"widget_enabled"`
-	WidgetChannelID             string                   `json:"widget_channel_id"`
-	VerificationLevel           int                      `json:"verification_level"`
-	DefaultMessageNotifications int                      `json:"default_message_notifications"`
-	ExplicitContentFilter       int                      `json:"explicit_content_filter"`
-	Roles                       map[string]Role          `json:"x_roles"`
-	Emojis                      []Emoji                  `json:"emojis"`
-	Features                    []string                 `json:"features"`
-	MFALevel                    int                      `json:"mfa_level"`
-	ApplicationID               string                   `json:"application_id"`
-	SystemChannelID             string                   `json:"system_channel_id"`
-	SystemChannelFlags          int                      `json:"system_channel_flags"`
-	RulesChannelID              string                   `json:"rules_channel_id"`
-	MaxPresences                int                      `json:"max_presences"`
-	MaxMembers                  int                      `json:"max_members"`
-	VanityURLCode               string                   `json:"vanity_url_code"`
-	Description                 string                   `json:"description"`
-	Banner                      string                   `json:"banner"`
-	PremiumTier                 int                      `json:"premium_tier"`
-	PremiumSubscriptionCount    int                      `json:"premium_subscription_count"`
-	PreferredLocale             string                   `json:"preferred_locale"`
-	PublicUpdatesChannelID      string                   `json:"public_updates_channel_id"`
-	MaxVideoChannelUsers        int                      `json:"max_video_channel_users"`
-	ApproximateMemberCount      int                      `json:"approximate_member_count"`
-	ApproximatePresenceCount    int                      `json:"approximate_presence_count"`
-	WelcomeScreen               map[string]interface{}   `json:"welcome_screen_enabled"`
-	NSFWLevel                   int                      `json:"nsfw_level"`
-	Stickers                    map[string]interface{}   `json:"stickers"`
-	PremiumProgressBarEnabled   bool                     `json:"premium_progress_bar_enabled"`
-	Members                     map[string]Member        `json:"x_members"`
-	Channels                    map[string]Channel       `json:"x_channels"`
-	JoinedAT                    string                   `json:"joined_at"`
-	Large                       bool                     `json:"large"`
-	MemberCount                 int                      `json:"member_count"`
-	VoiceStates                 []map[string]interface{} `json:"voice_states"`
-	Presences                   []map[string]interface{} `json:"presences"`
-	Threads                     []map[string]interface{} `json:"threads"`
-	StageInstances              []map[string]interface{} `json:"stage_instances"`
-	Unavailable                 bool                     `json:"unavailable"`
-	GuildScheduledEvents        []map[string]interface{} `json:"guild_scheduled_events"`
+	Id                          Snowflake                            `json:"id"`
+	Name                        string                               `json:"name"`
+	Icon                        string                               `json:"icon"`
+	IconHash                    string                               `json:"icon_hash"`
+	Splash                      string                               `json:"splash"`
+	DiscoverySplash             string                               `json:"discovery_splash"`
+	Owner                       bool                                 `json:"owner"`
+	OwnerID                     Snowflake                            `json:"owner_id"`
+	Permissions                 Permissions                          `json:"permissions"`
+	Region                      string                               `json:"region"`
+	AfkChannelID                Snowflake                            `json:"afk_channel_id"`
+	AfkTimeout                  int                                  `json:"afk_timeout"`
+	WidgetEnabled               bool                                 `json:"widget_enabled"`
+	WidgetChannelID             Snowflake                            `json:"widget_channel_id"`
+	VerificationLevel           int                                  `json:"verification_level"`
+	DefaultMessageNotifications int                                  `json:"default_message_notifications"`
+	ExplicitContentFilter       int                                  `json:"explicit_content_filter"`
+	Roles                       map[Snowflake]Role                   `json:"x_roles"`
+	Emojis                      []Emoji                              `json:"emojis"`
+	Features                    []string                             `json:"features"`
+	MFALevel                    int                                  `json:"mfa_level"`
+	ApplicationID               Snowflake                            `json:"application_id"`
+	SystemChannelID             Snowflake                            `json:"system_channel_id"`
+	SystemChannelFlags          int                                  `json:"system_channel_flags"`
+	RulesChannelID              Snowflake                            `json:"rules_channel_id"`
+	MaxPresences                int                                  `json:"max_presences"`
+	MaxMembers                  int                                  `json:"max_members"`
+	VanityURLCode               string                               `json:"vanity_url_code"`
+	Description                 string                               `json:"description"`
+	Banner                      string                               `json:"banner"`
+	PremiumTier                 int                                  `json:"premium_tier"`
+	PremiumSubscriptionCount    int                                  `json:"premium_subscription_count"`
+	PreferredLocale             string                               `json:"preferred_locale"`
+	PublicUpdatesChannelID      Snowflake                            `json:"public_updates_channel_id"`
+	MaxVideoChannelUsers        int                                  `json:"max_video_channel_users"`
+	ApproximateMemberCount      int                                  `json:"approximate_member_count"`
+	ApproximatePresenceCount    int                                  `json:"approximate_presence_count"`
+	WelcomeScreen               map[string]interface{}               `json:"welcome_screen_enabled"`
+	NSFWLevel                   int                                  `json:"nsfw_level"`
+	Stickers                    []map[string]interface{}             `json:"stickers"`
+	PremiumProgressBarEnabled   bool                                 `json:"premium_progress_bar_enabled"`
+	Members                     map[Snowflake]Member                 `json:"x_members"`
+	Channels                    map[Snowflake]Channel                `json:"x_channels"`
+	JoinedAT                    string                               `json:"joined_at"`
+	Large                       bool                                 `json:"large"`
+	MemberCount                 int                                  `json:"member_count"`
+	VoiceStates                 map[Snowflake]map[string]interface{} `json:"x_voice_states"` // by user id
+	Presences                   map[Snowflake]map[string]interface{} `json:"x_presences"`    // by user id
+	Threads                     []map[string]interface{}             `json:"threads"`
+	StageInstances              []map[string]interface{}             `json:"stage_instances"`
+	Unavailable                 bool                                 `json:"unavailable"`
+	GuildScheduledEvents        []map[string]interface{}             `json:"guild_scheduled_events"`
 }
 
 func UnmarshalGuild(payload interface{}) *Guild {
 	guild := &Guild{}
 	data, _ := json.Marshal(payload)
 	_ = json.Unmarshal(data, guild)
+	if d, ok := payload.(map[string]interface{}); ok {
+		presences, _ := d["presences"].([]interface{})
+		for _, o := range presences {
+			guild.putPresence(o)
+		}
+		states, _ := d["voice_states"].([]interface{})
+		for _, o := range states {
+			guild.putVoiceState(o)
+		}
+	}
 	return guild
 }
 
+func (guild *Guild) putPresence(payload interface{}) {
+	p, _ := payload.(map[string]interface{})
+	user, _ := p["user"].(map[string]interface{})
+	guild.Presences = upsert(guild.Presences, snowflakeOf(user["id"]), p, p["status"] != "offline")
+}
+
+func (guild *Guild) putVoiceState(payload interface{}) {
+	v, _ := payload.(map[string]interface{})
+	guild.VoiceStates = upsert(guild.VoiceStates, snowflakeOf(v["user_id"]), v, v["channel_id"] != nil)
+}
+
+// UnmarshalMembers adds the members to the guild, keeping those already present
 func (guild *Guild) UnmarshalMembers(objs []interface{}) {
-	var members = map[string]Member{}
+	if guild.Members == nil {
+		guild.Members = map[Snowflake]Member{}
+	}
 	for _, o := range objs {
 		uo := UnmarshalMember(o)
 		uo.GuildId = guild.Id
-		members[uo.User.Id] = *uo
+		guild.Members[uo.User.Id] = *uo
 	}
-	guild.Members = members
 }
 
 func (guild *Guild) UnmarshalRoles(objs []interface{}) {
-	var roles = map[string]Role{}
+	var roles = map[Snowflake]Role{}
 	for _, o := range objs {
 		uo := DataToRole(o)
 		uo.GuildId = guild.Id
@@ -87,7 +110,7 @@ func (guild *Guild) UnmarshalRoles(objs []interface{}) {
 }
 
 func (guild *Guild) UnmarshalChannels(objs []interface{}) {
-	var chs = map[string]Channel{}
+	var chs = map[Snowflake]Channel{}
 	for _, o := range objs {
 		uo := UnmarshalChannel(o)
 		chs[uo.Id] = *uo
@@ -13,28 +13,77 @@ type SlashCommandOption struct {
 }
 
 type InteractionData struct {
-	Id       string                 `json:"id"`
+	Id       Snowflake              `json:"id"`
 	Name     string                 `json:"name"`
 	Type     int                    `json:"type"`
 	Resolved map[string]interface{} `json:"resolved"`
 	Options  []SlashCommandOption   `json:"options"`
-	GuildId  string                 `json:"guild_id"`
-	TargetId string                 `json:"target_id"`
+	GuildId  Snowflake              `json:"guild_id"`
+	TargetId Snowflake              `json:"target_id"`
+}
+
+// ResolvedData holds the users, members, roles, channels,
+// messages and attachments referenced by an interaction
+type ResolvedData struct {
+	Users       map[Snowflake]User       `json:"users"`
+	Members     map[Snowflake]Member     `json:"members"`
+	Roles       map[Snowflake]Role       `json:"roles"`
+	Channels    map[Snowflake]Channel    `json:"channels"`
+	Messages    map[Snowflake]Message    `json:"messages"`
+	Attachments map[Snowflake]Attachment `json:"attachments"`
+}
+
+// link fills the user of resolved members, which discord omits
+func (r *ResolvedData) link() {
+	for id, m := range r.Members {
+		m.User = r.Users[id]
+		r.Members[id] = m
+	}
+}
+
+// Selection maps the chosen ids of a select menu to their resolved objects
+func (r *ResolvedData) Selection(values []string) Selection {
+	sel := Selection{Values: values}
+	for _, value := range values {
+		id := Snowflake(value)
+		if u, ok := r.Users[id]; ok {
+			sel.Users = append(sel.Users, u)
+		}
+		if m, ok := r.Members[id]; ok {
+			sel.Members = append(sel.Members, m)
+		}
+		if ro, ok := r.Roles[id]; ok {
+			sel.Roles = append(sel.Roles, ro)
+		}
+		if ch, ok := r.Channels[id]; ok {
+			sel.Channels = append(sel.Channels, ch)
+		}
+	}
+	return sel
+}
+
+// ResolvedData decodes the raw resolved map of the interaction
+func (d *InteractionData) ResolvedData() ResolvedData {
+	r := ResolvedData{}
+	data, _ := json.Marshal(d.Resolved)
+	_ = json.Unmarshal(data, &r)
+	r.link()
+	return r
 }
 
 type Interaction struct {
-	Id             string          `json:"id"`
-	ApplicationId  string          `json:"application_id"`
+	Id             Snowflake       `json:"id"`
+	ApplicationId  Snowflake       `json:"application_id"`
 	Type           int             `json:"type"`
 	Data           InteractionData `json:"data"`
-	GuildId        string          `json:"guild_id"`
-	ChannelId      string          `json:"channel_id"`
+	GuildId        Snowflake       `json:"guild_id"`
+	ChannelId      Snowflake       `json:"channel_id"`
 	Member         Member          `json:"member"`
 	User           User            `json:"user"`
 	Token          string          `json:"token"`
 	Version        int             `json:"version"`
 	Message        interface{}     `json:"message"`
-	AppPermissions string          `json:"app_permissions"`
+	AppPermissions Permissions     `json:"app_permissions"`
 	Locale         string          `json:"locale"`
 	GuildLocale    string          `json:"guild_locale"`
 }
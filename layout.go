@@ -0,0 +1,238 @@
+package disgo
+
+import (
+	"fmt"
+)
+
+// LayoutComponent is a component of a message using the components v2 layout,
+// implemented by ActionRow, Section, TextDisplay, Thumbnail, MediaGallery,
+// FileComponent, Separator and Container
+type LayoutComponent interface {
+	layoutType() int
+	marshalLayout(l *layoutBuilder) map[string]interface{}
+}
+
+// layoutBuilder carries the callbacks and counters while marshalling a Layout
+type layoutBuilder struct {
+	callbacks *Callbacks
+	ids       map[string]bool
+	count     int
+}
+
+func (l *layoutBuilder) add(n int) {
+	l.count += n
+}
+
+// TextDisplay is a markdown text block
+type TextDisplay struct {
+	Content string
+}
+
+// Thumbnail is a small image, only allowed as the accessory of a Section
+type Thumbnail struct {
+	URL         string // http(s) url or attachment://<filename>
+	Description string
+	Spoiler     bool
+}
+
+// MediaItem is an image or video of a MediaGallery
+type MediaItem struct {
+	URL         string // http(s) url or attachment://<filename>
+	Description string
+	Spoiler     bool
+}
+
+// MediaGallery displays 1 to 10 media items in a grid
+type MediaGallery struct {
+	Items []MediaItem
+}
+
+// FileComponent displays an uploaded file referenced as attachment://<filename>
+type FileComponent struct {
+	URL     string
+	Spoiler bool
+}
+
+// Separator adds vertical padding and an optional divider line between components
+type Separator struct {
+	Divider bool
+	Spacing int // 1 (small) or 2 (large), default: 1
+}
+
+// Section shows 1 to 3 text displays next to a Thumbnail or Button accessory
+type Section struct {
+	Components []TextDisplay
+	Accessory  LayoutComponent // *Thumbnail or *Button
+}
+
+// Container groups components in a box with an optional accent color
+type Container struct {
+	Components  []LayoutComponent
+	AccentColor int
+	Spoiler     bool
+}
+
+func (t *TextDisplay) layoutType() int   { return 10 }
+func (t *Thumbnail) layoutType() int     { return 11 }
+func (g *MediaGallery) layoutType() int  { return 12 }
+func (f *FileComponent) layoutType() int { return 13 }
+func (s *Separator) layoutType() int     { return 14 }
+func (s *Section) layoutType() int       { return 9 }
+func (c *Container) layoutType() int     { return 17 }
+func (row *ActionRow) layoutType() int   { return 1 }
+func (b *Button) layoutType() int        { return 2 }
+
+func (t *TextDisplay) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	if t.Content == "" {
+		panic("TextDisplay {content} must be set")
+	}
+	l.add(1)
+	return map[string]interface{}{"type": 10, "content": t.Content}
+}
+
+func media(url string, description string, spoiler bool) map[string]interface{} {
+	if url == "" {
+		panic("Media {url} must be set")
+	}
+	m := map[string]interface{}{"media": map[string]interface{}{"url": url}}
+	if description != "" {
+		m["description"] = description
+	}
+	if spoiler {
+		m["spoiler"] = true
+	}
+	return m
+}
+
+func (t *Thumbnail) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	l.add(1)
+	th := media(t.URL, t.Description, t.Spoiler)
+	th["type"] = 11
+	return th
+}
+
+func (g *MediaGallery) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	if len(g.Items) < 1 || len(g.Items) > 10 {
+		panic("MediaGallery must have between 1 and 10 {items}")
+	}
+	l.add(1)
+	var items []interface{}
+	for _, item := range g.Items {
+		items = append(items, media(item.URL, item.Description, item.Spoiler))
+	}
+	return map[string]interface{}{"type": 12, "items": items}
+}
+
+func (f *FileComponent) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	if len(f.URL) < 14 || f.URL[:13] != "attachment://" {
+		panic(fmt.Sprintf("FileComponent {url} must reference an attachment://<filename>, got (%s)", f.URL))
+	}
+	l.add(1)
+	file := map[string]interface{}{"type": 13, "file": map[string]interface{}{"url": f.URL}}
+	if f.Spoiler {
+		file["spoiler"] = true
+	}
+	return file
+}
+
+func (s *Separator) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	if s.Spacing != 2 {
+		s.Spacing = 1
+	}
+	l.add(1)
+	return map[string]interface{}{"type": 14, "divider": s.Divider, "spacing": s.Spacing}
+}
+
+func (s *Section) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	if len(s.Components) < 1 || len(s.Components) > 3 {
+		panic("Section must have between 1 and 3 {components}")
+	}
+	if s.Accessory == nil {
+		panic("Section {accessory} must be set")
+	}
+	switch s.Accessory.layoutType() {
+	case 2, 11:
+	default:
+		panic("Section {accessory} must be a Thumbnail or a Button")
+	}
+	l.add(1)
+	var texts []interface{}
+	for _, text := range s.Components {
+		texts = append(texts, text.marshalLayout(l))
+	}
+	return map[string]interface{}{"type": 9, "components": texts, "accessory": s.Accessory.marshalLayout(l)}
+}
+
+func (c *Container) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	l.add(1)
+	var children []interface{}
+	for _, child := range c.Components {
+		switch child.layoutType() {
+		case 1, 9, 10, 12, 13, 14:
+		default:
+			panic(fmt.Sprintf("Container can not contain a component of type %d", child.layoutType()))
+		}
+		children = append(children, child.marshalLayout(l))
+	}
+	container := map[string]interface{}{"type": 17, "components": children}
+	if c.AccentColor != 0 {
+		container["accent_color"] = c.AccentColor
+	}
+	if c.Spoiler {
+		container["spoiler"] = true
+	}
+	return container
+}
+
+func (row *ActionRow) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	tmp := row.marshal(l.callbacks, nil, l.ids)
+	if tmp == nil {
+		panic("ActionRow must have buttons or a select menu")
+	}
+	l.add(1 + len(tmp["components"].([]interface{})))
+	return tmp
+}
+
+func (b *Button) marshalLayout(l *layoutBuilder) map[string]interface{} {
+	l.add(1)
+	btn := b.marshal(l.callbacks, nil)
+	if b.CustomId != "" {
+		l.ids[b.CustomId] = true
+	}
+	return btn
+}
+
+// Layout builds a message out of components v2, which
+// can not be combined with content, embeds or a View
+type Layout struct {
+	Components []LayoutComponent
+	Timeout    float64 // seconds before callbacks of interactive components are removed, default: 15 * 60 seconds
+}
+
+func (l *Layout) Add(components ...LayoutComponent) *Layout {
+	l.Components = append(l.Components, components...)
+	return l
+}
+
+func (l *Layout) marshal(callbacks *Callbacks) []interface{} {
+	if l.Timeout <= 0 {
+		l.Timeout = 15 * 60
+	}
+	b := &layoutBuilder{callbacks: callbacks, ids: map[string]bool{}}
+	var c []interface{}
+	for _, comp := range l.Components {
+		switch comp.layoutType() {
+		case 1, 9, 10, 12, 13, 14, 17:
+		default:
+			panic(fmt.Sprintf("Layout can not contain a top-level component of type %d", comp.layoutType()))
+		}
+		c = append(c, comp.marshalLayout(b))
+	}
+	if b.count > 40 {
+		panic(fmt.Sprintf("Layout can contain max 40 components, got %d", b.count))
+	}
+	if len(b.ids) > 0 {
+		go ScheduleDeletion(l.Timeout, callbacks, b.ids)
+	}
+	return c
+}
@@ -0,0 +1,103 @@
+package disgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"unicode/utf8"
+)
+
+// Locales contains every locale code supported by discord
+var Locales = map[string]string{
+	"id":     "Indonesian",
+	"da":     "Danish",
+	"de":     "German",
+	"en-GB":  "English, UK",
+	"en-US":  "English, US",
+	"es-ES":  "Spanish",
+	"es-419": "Spanish, LATAM",
+	"fr":     "French",
+	"hr":     "Croatian",
+	"it":     "Italian",
+	"lt":     "Lithuanian",
+	"hu":     "Hungarian",
+	"nl":     "Dutch",
+	"no":     "Norwegian",
+	"pl":     "Polish",
+	"pt-BR":  "Portuguese, Brazilian",
+	"ro":     "Romanian, Romania",
+	"fi":     "Finnish",
+	"sv-SE":  "Swedish",
+	"vi":     "Vietnamese",
+	"tr":     "Turkish",
+	"cs":     "Czech",
+	"el":     "Greek",
+	"bg":     "Bulgarian",
+	"ru":     "Russian",
+	"uk":     "Ukrainian",
+	"hi":     "Hindi",
+	"th":     "Thai",
+	"zh-CN":  "Chinese, China",
+	"ja":     "Japanese",
+	"zh-TW":  "Chinese, Taiwan",
+	"ko":     "Korean",
+}
+
+func checkLocalizations(owner string, field string, locs map[string]string, limit int) {
+	for locale, value := range locs {
+		if _, ok := Locales[locale]; !ok {
+			panic(fmt.Sprintf("(%s) {%s} has unsupported locale (%s)", owner, field, locale))
+		}
+		if value == "" || utf8.RuneCountInString(value) > limit {
+			panic(fmt.Sprintf(
+				"(%s) {%s} for locale (%s) must be between 1 and %d characters", owner, field, locale, limit))
+		}
+	}
+}
+
+// Localization is the catalog entry of a command, option or choice
+type Localization struct {
+	Name        map[string]string       `json:"name"`
+	Description map[string]string       `json:"description"`
+	Options     map[string]Localization `json:"options"`
+	Choices     map[string]Localization `json:"choices"`
+}
+
+// LoadLocalizations reads a JSON translation catalog keyed by command name and
+// fills the localization maps of the command, its options and their choices;
+// a command missing from the catalog is left untranslated
+func (cmd *ApplicationCommand) LoadLocalizations(path string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading localization catalog %s: %w", path, err)
+	}
+	var catalog map[string]Localization
+	if err = json.Unmarshal(bs, &catalog); err != nil {
+		return fmt.Errorf("parsing localization catalog %s: %w", path, err)
+	}
+	loc, ok := catalog[cmd.Name]
+	if !ok {
+		return nil
+	}
+	cmd.NameLocalizations = loc.Name
+	cmd.DescriptionLocalizations = loc.Description
+	localizeOptions(cmd.Options, loc.Options)
+	return nil
+}
+
+func localizeOptions(options []CommandOption, locs map[string]Localization) {
+	for i := range options {
+		loc, ok := locs[options[i].Name]
+		if !ok {
+			continue
+		}
+		options[i].NameLocalizations = loc.Name
+		options[i].DescriptionLocalizations = loc.Description
+		for j := range options[i].Choices {
+			if cl, ok := loc.Choices[options[i].Choices[j].Name]; ok {
+				options[i].Choices[j].NameLocalizations = cl.Name
+			}
+		}
+		localizeOptions(options[i].Options, loc.Options)
+	}
+}
@@ -0,0 +1,96 @@
+package disgo
+
+import (
+	"container/list"
+	"time"
+)
+
+type lruEntry struct {
+	key      string
+	value    interface{}
+	lastSeen time.Time
+	aged     *list.Element // position in lru.age
+}
+
+// lru is a size and age bounded cache, evicting the least recently used entries
+// over the size bound and the entries not put within the ttl;
+// it is not safe for concurrent use on its own
+type lru struct {
+	max     int           // 0: unbounded
+	ttl     time.Duration // 0: never expires
+	order   *list.List    // by last use, most recent first
+	age     *list.List    // by last put, most recent first
+	entries map[string]*list.Element
+	onEvict func(key string, value interface{})
+}
+
+func newLRU(max int, ttl time.Duration, onEvict func(key string, value interface{})) *lru {
+	return &lru{max: max, ttl: ttl, order: list.New(), age: list.New(), entries: map[string]*list.Element{}, onEvict: onEvict}
+}
+
+func (c *lru) put(key string, value interface{}) {
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.lastSeen = time.Now()
+		c.order.MoveToFront(el)
+		c.age.MoveToFront(entry.aged)
+	} else {
+		entry := &lruEntry{key: key, value: value, lastSeen: time.Now()}
+		entry.aged = c.age.PushFront(entry)
+		c.entries[key] = c.order.PushFront(entry)
+	}
+	c.prune()
+}
+
+// has reports whether the key is cached, without counting as a use
+func (c *lru) has(key string) bool {
+	_, ok := c.entries[key]
+	return ok
+}
+
+func (c *lru) get(key string) (interface{}, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if c.ttl > 0 && time.Since(entry.lastSeen) > c.ttl {
+		c.evict(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *lru) remove(key string) {
+	if el, ok := c.entries[key]; ok {
+		c.age.Remove(el.Value.(*lruEntry).aged)
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+func (c *lru) evict(el *list.Element) {
+	entry := el.Value.(*lruEntry)
+	c.age.Remove(entry.aged)
+	c.order.Remove(el)
+	delete(c.entries, entry.key)
+	if c.onEvict != nil {
+		c.onEvict(entry.key, entry.value)
+	}
+}
+
+// prune drops the least recently used entries over the size bound and the expired ones
+func (c *lru) prune() {
+	for c.max > 0 && c.order.Len() > c.max {
+		c.evict(c.order.Back())
+	}
+	for c.ttl > 0 && c.age.Len() > 0 {
+		entry := c.age.Back().Value.(*lruEntry)
+		if time.Since(entry.lastSeen) <= c.ttl {
+			break
+		}
+		c.evict(c.entries[entry.key])
+	}
+}
@@ -5,38 +5,176 @@ import (
 )
 
 type Message struct {
-	Id                 string                   `json:"id"`
-	ChannelId          string                   `json:"channel_id"`
-	Author             User                     `json:"author"`
-	Content            string                   `json:"content"`
-	Timestamp          string                   `json:"timestamp"`
-	EditedTimestamp    string                   `json:"edited_timestamp"`
-	TTS                bool                     `json:"tts"`
-	MentionEveryone    bool                     `json:"mention_everyone"`
-	Mentions           []map[string]interface{} `json:"mentions"`
-	RoleMentions       []string                 `json:"role_mentions"`
-	ChannelMentions    []string                 `json:"channel_mentions"`
-	Attachments        []map[string]interface{} `json:"attachments"`
-	Embeds             []Embed                  `json:"embeds"`
-	Reactions          []map[string]interface{} `json:"reactions"`
-	Pinned             bool                     `json:"pinned"`
-	WebhookId          string                   `json:"webhook_id"`
-	Type               int                      `json:"types"`
-	Activity           map[string]interface{}   `json:"activity"`
-	Application        map[string]interface{}   `json:"application"`
-	ApplicationId      string                   `json:"application_id"`
-	MessageReference   map[string]interface{}   `json:"message_reference"`
-	Flags              int                      `json:"flags"`
-	ReferencedMessages map[string]interface{}   `json:"reference"`
-	Interaction        map[string]interface{}   `json:"interaction"`
-	Thread             map[string]interface{}   `json:"thread"`
-	Components         []map[string]interface{} `json:"components"`
-	Stickers           []map[string]interface{} `json:"sticker_items"`
+	Id                Snowflake           `json:"id"`
+	ChannelId         Snowflake           `json:"channel_id"`
+	GuildId           Snowflake           `json:"guild_id"`
+	Author            User                `json:"author"`
+	Member            *Member             `json:"member"` // guild messages of users only
+	Content           string              `json:"content"`
+	Timestamp         string              `json:"timestamp"`
+	EditedTimestamp   string              `json:"edited_timestamp"`
+	TTS               bool                `json:"tts"`
+	MentionEveryone   bool                `json:"mention_everyone"`
+	Mentions          []User              `json:"mentions"`
+	RoleMentions      []Snowflake         `json:"mention_roles"`
+	ChannelMentions   []ChannelMention    `json:"mention_channels"`
+	Attachments       []Attachment        `json:"attachments"`
+	Embeds            []Embed             `json:"embeds"`
+	Reactions         []Reaction          `json:"reactions"`
+	Pinned            bool                `json:"pinned"`
+	WebhookId         Snowflake           `json:"webhook_id"`
+	Type              int                 `json:"type"`
+	Activity          *MessageActivity    `json:"activity"`
+	Application       *Application        `json:"application"`
+	ApplicationId     Snowflake           `json:"application_id"`
+	MessageReference  *MessageReference   `json:"message_reference"`
+	Flags             int                 `json:"flags"`
+	ReferencedMessage *Message            `json:"referenced_message"`
+	Interaction       *MessageInteraction `json:"interaction"`
+	Thread            *Channel            `json:"thread"`
+	Components        []MessageComponent  `json:"components"`
+	Stickers          []StickerItem       `json:"sticker_items"`
+	Position          int                 `json:"position"`
+}
+
+// ChannelMention is a channel of another guild mentioned in a crossposted message
+type ChannelMention struct {
+	Id      Snowflake   `json:"id"`
+	GuildId Snowflake   `json:"guild_id"`
+	Type    ChannelType `json:"type"`
+	Name    string      `json:"name"`
+}
+
+type Reaction struct {
+	Count        int `json:"count"`
+	CountDetails struct {
+		Burst  int `json:"burst"`
+		Normal int `json:"normal"`
+	} `json:"count_details"`
+	Me          bool         `json:"me"`
+	MeBurst     bool         `json:"me_burst"`
+	Emoji       PartialEmoji `json:"emoji"`
+	BurstColors []string     `json:"burst_colors"`
+}
+
+type MessageActivity struct {
+	Type    int    `json:"type"` // 1 (join), 2 (spectate), 3 (listen), 5 (join request)
+	PartyId string `json:"party_id"`
+}
+
+// Application is the partial application of a rich presence message
+type Application struct {
+	Id          Snowflake `json:"id"`
+	Name        string    `json:"name"`
+	Icon        string    `json:"icon"`
+	Description string    `json:"description"`
+	CoverImage  string    `json:"cover_image"`
+}
+
+// MessageReference points to the message replied to, crossposted, pinned or forwarded
+type MessageReference struct {
+	Type            int       `json:"type"` // 0 (default), 1 (forward)
+	MessageId       Snowflake `json:"message_id"`
+	ChannelId       Snowflake `json:"channel_id"`
+	GuildId         Snowflake `json:"guild_id"`
+	FailIfNotExists bool      `json:"fail_if_not_exists"`
+}
+
+// MessageInteraction is the interaction a message responds to
+type MessageInteraction struct {
+	Id     Snowflake `json:"id"`
+	Type   int       `json:"type"`
+	Name   string    `json:"name"`
+	User   User      `json:"user"`
+	Member *Member   `json:"member"`
+}
+
+type StickerItem struct {
+	Id         Snowflake `json:"id"`
+	Name       string    `json:"name"`
+	FormatType int       `json:"format_type"` // 1 (png), 2 (apng), 3 (lottie), 4 (gif)
+}
+
+// MessageComponent is a component of a received message, action rows,
+// sections and containers carry their children in Components
+type MessageComponent struct {
+	Type          int                `json:"type"`
+	Id            int                `json:"id,omitempty"`
+	CustomId      string             `json:"custom_id,omitempty"`
+	Style         int                `json:"style,omitempty"`
+	Label         string             `json:"label,omitempty"`
+	Emoji         *PartialEmoji      `json:"emoji,omitempty"`
+	URL           string             `json:"url,omitempty"`
+	SkuId         Snowflake          `json:"sku_id,omitempty"`
+	Disabled      bool               `json:"disabled,omitempty"`
+	Placeholder   string             `json:"placeholder,omitempty"`
+	MinValues     *int               `json:"min_values,omitempty"`
+	MaxValues     *int               `json:"max_values,omitempty"`
+	Options       []ComponentOption  `json:"options,omitempty"`
+	ChannelTypes  []ChannelType      `json:"channel_types,omitempty"`
+	DefaultValues []DefaultValue     `json:"default_values,omitempty"`
+	Content       string             `json:"content,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	Spoiler       bool               `json:"spoiler,omitempty"`
+	Divider       *bool              `json:"divider,omitempty"`
+	Spacing       int                `json:"spacing,omitempty"`
+	AccentColor   *int               `json:"accent_color,omitempty"`
+	Accessory     *MessageComponent  `json:"accessory,omitempty"`
+	Media         *UnfurledMedia     `json:"media,omitempty"`
+	File          *UnfurledMedia     `json:"file,omitempty"`
+	Items         []MediaGalleryItem `json:"items,omitempty"`
+	Components    []MessageComponent `json:"components,omitempty"`
+}
+
+// ComponentOption is an option of a received string select menu
+type ComponentOption struct {
+	Label       string        `json:"label"`
+	Value       string        `json:"value"`
+	Description string        `json:"description,omitempty"`
+	Emoji       *PartialEmoji `json:"emoji,omitempty"`
+	Default     bool          `json:"default,omitempty"`
+}
+
+// UnfurledMedia is the media of a thumbnail, gallery item or file component
+type UnfurledMedia struct {
+	URL         string `json:"url"`
+	ProxyURL    string `json:"proxy_url,omitempty"`
+	Width       int    `json:"width,omitempty"`
+	Height      int    `json:"height,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+type MediaGalleryItem struct {
+	Media       UnfurledMedia `json:"media"`
+	Description string        `json:"description,omitempty"`
+	Spoiler     bool          `json:"spoiler,omitempty"`
 }
 
 func UnmarshalMessage(payload interface{}) *Message {
 	msg := &Message{}
 	data, _ := json.Marshal(payload)
 	_ = json.Unmarshal(data, msg)
+	if msg.Member != nil {
+		msg.Member.User = msg.Author
+		msg.Member.GuildId = msg.GuildId
+	}
 	return msg
 }
+
+// MessageDelete is the payload of a deleted message,
+// Cached is nil when the message was not in the cache
+type MessageDelete struct {
+	Id        Snowflake
+	ChannelId Snowflake
+	GuildId   Snowflake
+	Cached    *Message
+}
+
+// MessageBulkDelete is the payload of messages deleted at once,
+// Cached contains the ones found in the cache
+type MessageBulkDelete struct {
+	Ids       []Snowflake
+	ChannelId Snowflake
+	GuildId   Snowflake
+	Cached    []Message
+}
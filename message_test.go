@@ -0,0 +1,375 @@
+package disgo
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const replyPayload = `{
+	"id": "1290400000000000001",
+	"type": 19,
+	"channel_id": "1100000000000000010",
+	"guild_id": "1100000000000000001",
+	"author": {"id": "80351110224678912", "username": "nelly", "discriminator": "0", "avatar": "8342729096ea3675442027381ff50dfe", "public_flags": 64},
+	"member": {"roles": ["1100000000000000100"], "nick": "Nelly", "joined_at": "2021-05-01T10:00:00.000000+00:00", "premium_since": null, "deaf": false, "mute": false, "flags": 0, "pending": false, "communication_disabled_until": null},
+	"content": "agreed, ship it",
+	"timestamp": "2024-09-30T12:00:00.000000+00:00",
+	"edited_timestamp": null,
+	"tts": false,
+	"mention_everyone": false,
+	"mentions": [{"id": "53908232506183680", "username": "mason", "discriminator": "0", "avatar": null, "public_flags": 0}],
+	"mention_roles": [],
+	"attachments": [{"id": "1290400000000000100", "filename": "build.log", "size": 2048, "url": "https://cdn.discordapp.com/attachments/1/2/build.log", "proxy_url": "https://media.discordapp.net/attachments/1/2/build.log", "content_type": "text/plain; charset=utf-8"}],
+	"embeds": [],
+	"reactions": [{"count": 3, "count_details": {"burst": 1, "normal": 2}, "me": true, "me_burst": false, "burst_colors": ["#ff0000"], "emoji": {"id": null, "name": "👍"}}],
+	"pinned": false,
+	"flags": 0,
+	"message_reference": {"type": 0, "message_id": "1290300000000000001", "channel_id": "1100000000000000010", "guild_id": "1100000000000000001"},
+	"referenced_message": {
+		"id": "1290300000000000001",
+		"type": 0,
+		"channel_id": "1100000000000000010",
+		"author": {"id": "53908232506183680", "username": "mason", "discriminator": "0", "avatar": null},
+		"content": "ready for review",
+		"timestamp": "2024-09-30T11:58:00.000000+00:00",
+		"edited_timestamp": "2024-09-30T11:59:00.000000+00:00",
+		"mentions": [],
+		"mention_roles": [],
+		"attachments": [],
+		"embeds": [],
+		"pinned": false,
+		"flags": 0
+	}
+}`
+
+const crosspostPayload = `{
+	"id": "1290400000000000002",
+	"type": 0,
+	"channel_id": "1100000000000000011",
+	"author": {"id": "1290100000000000001", "username": "Announcements", "discriminator": "0000", "avatar": null, "bot": true},
+	"webhook_id": "1290100000000000001",
+	"content": "v2 is out",
+	"timestamp": "2024-09-30T12:05:00.000000+00:00",
+	"edited_timestamp": null,
+	"mentions": [],
+	"mention_roles": [],
+	"mention_channels": [{"id": "1200000000000000010", "guild_id": "1200000000000000001", "type": 5, "name": "releases"}],
+	"attachments": [],
+	"embeds": [],
+	"pinned": false,
+	"flags": 2,
+	"message_reference": {"message_id": "1290000000000000005", "channel_id": "1200000000000000010", "guild_id": "1200000000000000001"}
+}`
+
+const forwardPayload = `{
+	"id": "1290400000000000003",
+	"type": 0,
+	"channel_id": "1100000000000000010",
+	"guild_id": "1100000000000000001",
+	"author": {"id": "80351110224678912", "username": "nelly", "discriminator": "0", "avatar": null},
+	"member": {"roles": [], "joined_at": "2021-05-01T10:00:00.000000+00:00", "deaf": false, "mute": false},
+	"content": "",
+	"timestamp": "2024-09-30T12:10:00.000000+00:00",
+	"edited_timestamp": null,
+	"mentions": [],
+	"mention_roles": [],
+	"attachments": [],
+	"embeds": [],
+	"pinned": false,
+	"flags": 16384,
+	"message_reference": {"type": 1, "message_id": "1290000000000000009", "channel_id": "1200000000000000010", "guild_id": "1200000000000000001"},
+	"message_snapshots": [{"message": {"type": 0, "content": "v2 is out", "timestamp": "2024-09-30T12:04:00.000000+00:00", "flags": 0}}]
+}`
+
+const interactionPayload = `{
+	"id": "1290400000000000004",
+	"type": 20,
+	"channel_id": "1100000000000000010",
+	"guild_id": "1100000000000000001",
+	"application_id": "1000000000000000001",
+	"author": {"id": "1000000000000000001", "username": "disgo", "discriminator": "0", "avatar": null, "bot": true},
+	"content": "pong",
+	"timestamp": "2024-09-30T12:15:00.000000+00:00",
+	"edited_timestamp": null,
+	"mentions": [],
+	"mention_roles": [],
+	"attachments": [],
+	"embeds": [],
+	"pinned": false,
+	"flags": 64,
+	"webhook_id": "1000000000000000001",
+	"interaction": {
+		"id": "1290400000000000040",
+		"type": 2,
+		"name": "ping",
+		"user": {"id": "80351110224678912", "username": "nelly", "discriminator": "0", "avatar": null},
+		"member": {"roles": ["1100000000000000100"], "joined_at": "2021-05-01T10:00:00.000000+00:00", "deaf": false, "mute": false}
+	},
+	"components": [{"type": 1, "components": [
+		{"type": 2, "style": 1, "custom_id": "again", "label": "Again", "emoji": {"name": "🔁"}},
+		{"type": 2, "style": 5, "url": "https://discord.com/developers/docs", "label": "Docs", "disabled": true}
+	]}, {"type": 1, "components": [
+		{"type": 3, "custom_id": "pick", "placeholder": "Pick one", "min_values": 0, "max_values": 1, "options": [{"label": "One", "value": "1", "default": true}, {"label": "Two", "value": "2"}]}
+	]}]
+}`
+
+const stickerPayload = `{
+	"id": "1290400000000000005",
+	"type": 0,
+	"channel_id": "1100000000000000010",
+	"guild_id": "1100000000000000001",
+	"author": {"id": "53908232506183680", "username": "mason", "discriminator": "0", "avatar": null},
+	"member": {"roles": [], "joined_at": "2022-01-01T00:00:00.000000+00:00", "deaf": false, "mute": false},
+	"content": "",
+	"timestamp": "2024-09-30T12:20:00.000000+00:00",
+	"edited_timestamp": null,
+	"mentions": [],
+	"mention_roles": [],
+	"attachments": [],
+	"embeds": [],
+	"pinned": false,
+	"flags": 0,
+	"sticker_items": [{"id": "749054660769218631", "name": "Wave", "format_type": 3}]
+}`
+
+const componentsV2Payload = `{
+	"id": "1290400000000000006",
+	"type": 0,
+	"channel_id": "1100000000000000010",
+	"guild_id": "1100000000000000001",
+	"application_id": "1000000000000000001",
+	"author": {"id": "1000000000000000001", "username": "disgo", "discriminator": "0", "avatar": null, "bot": true},
+	"content": "",
+	"timestamp": "2024-09-30T12:25:00.000000+00:00",
+	"edited_timestamp": null,
+	"mentions": [],
+	"mention_roles": [],
+	"attachments": [{"id": "1290400000000000600", "filename": "report.pdf", "size": 10240, "url": "https://cdn.discordapp.com/attachments/1/2/report.pdf", "proxy_url": "https://media.discordapp.net/attachments/1/2/report.pdf", "content_type": "application/pdf"}],
+	"embeds": [],
+	"pinned": false,
+	"flags": 32768,
+	"components": [{"type": 17, "id": 1, "accent_color": 5793266, "spoiler": false, "components": [
+		{"type": 9, "id": 2, "components": [{"type": 10, "id": 3, "content": "## Weekly report"}],
+			"accessory": {"type": 11, "id": 4, "media": {"url": "https://cdn.discordapp.com/icons/1/a.png", "width": 128, "height": 128, "content_type": "image/png"}, "description": "icon"}},
+		{"type": 14, "id": 5, "divider": false, "spacing": 2},
+		{"type": 12, "id": 6, "items": [{"media": {"url": "https://cdn.discordapp.com/attachments/1/2/chart.png"}, "description": "chart", "spoiler": true}]},
+		{"type": 13, "id": 7, "file": {"url": "attachment://report.pdf"}},
+		{"type": 1, "id": 8, "components": [{"type": 8, "id": 9, "custom_id": "channel", "channel_types": [0, 5], "default_values": [{"id": "1100000000000000010", "type": "channel"}]}]}
+	]}]
+}`
+
+func decodeMessage(t *testing.T, payload string) *Message {
+	t.Helper()
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		t.Fatal(err)
+	}
+	return UnmarshalMessage(data)
+}
+
+func TestUnmarshalMessageReply(t *testing.T) {
+	m := decodeMessage(t, replyPayload)
+	if m.Type != 19 || m.Author.Id != "80351110224678912" || m.Content != "agreed, ship it" {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if m.Member == nil {
+		t.Fatal("member is not decoded")
+	}
+	if m.Member.User.Id != m.Author.Id || m.Member.GuildId != "1100000000000000001" || m.Member.Nickname != "Nelly" {
+		t.Errorf("member is not backfilled: %+v", m.Member)
+	}
+	if len(m.Member.Roles) != 1 || m.Member.Roles[0] != "1100000000000000100" {
+		t.Errorf("unexpected member roles %v", m.Member.Roles)
+	}
+	ref := m.MessageReference
+	if ref == nil || ref.Type != 0 || ref.MessageId != "1290300000000000001" || ref.GuildId != "1100000000000000001" {
+		t.Errorf("unexpected message reference %+v", ref)
+	}
+	replied := m.ReferencedMessage
+	if replied == nil || replied.Id != "1290300000000000001" || replied.Author.Id != "53908232506183680" {
+		t.Fatalf("unexpected referenced message %+v", replied)
+	}
+	if replied.EditedTimestamp != "2024-09-30T11:59:00.000000+00:00" || replied.Member != nil {
+		t.Errorf("unexpected referenced message %+v", replied)
+	}
+	if len(m.Mentions) != 1 || m.Mentions[0].Id != "53908232506183680" {
+		t.Errorf("unexpected mentions %v", m.Mentions)
+	}
+	if len(m.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(m.Attachments))
+	}
+	if a := m.Attachments[0]; a.ID != "1290400000000000100" || a.Filename != "build.log" || a.Size != 2048 || a.ContentType != "text/plain; charset=utf-8" {
+		t.Errorf("unexpected attachment %+v", a)
+	}
+	if len(m.Reactions) != 1 {
+		t.Fatalf("expected 1 reaction, got %d", len(m.Reactions))
+	}
+	r := m.Reactions[0]
+	if r.Count != 3 || r.CountDetails.Burst != 1 || r.CountDetails.Normal != 2 || !r.Me || r.Emoji.Name != "👍" || r.Emoji.Id != "" {
+		t.Errorf("unexpected reaction %+v", r)
+	}
+	if len(r.BurstColors) != 1 || r.BurstColors[0] != "#ff0000" {
+		t.Errorf("unexpected burst colors %v", r.BurstColors)
+	}
+}
+
+func TestUnmarshalMessageCrosspost(t *testing.T) {
+	m := decodeMessage(t, crosspostPayload)
+	if m.Type != 0 || m.Flags != 2 || m.WebhookId != "1290100000000000001" || !m.Author.Bot {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if m.Member != nil || m.GuildId != "" {
+		t.Errorf("webhook message has a member %+v", m.Member)
+	}
+	ref := m.MessageReference
+	if ref == nil || ref.Type != 0 || ref.MessageId != "1290000000000000005" || ref.ChannelId != "1200000000000000010" {
+		t.Errorf("unexpected message reference %+v", ref)
+	}
+	if m.ReferencedMessage != nil {
+		t.Errorf("crosspost has a referenced message %+v", m.ReferencedMessage)
+	}
+	if len(m.ChannelMentions) != 1 || m.ChannelMentions[0].Type != 5 || m.ChannelMentions[0].Name != "releases" {
+		t.Errorf("unexpected channel mentions %+v", m.ChannelMentions)
+	}
+}
+
+func TestUnmarshalMessageForward(t *testing.T) {
+	m := decodeMessage(t, forwardPayload)
+	if m.Type != 0 || m.Flags != 16384 || m.Content != "" {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	ref := m.MessageReference
+	if ref == nil || ref.Type != 1 || ref.MessageId != "1290000000000000009" || ref.GuildId != "1200000000000000001" {
+		t.Errorf("unexpected message reference %+v", ref)
+	}
+	if m.ReferencedMessage != nil {
+		t.Errorf("forward has a referenced message %+v", m.ReferencedMessage)
+	}
+	if m.Member == nil || m.Member.User.Id != "80351110224678912" || m.Member.GuildId != "1100000000000000001" {
+		t.Errorf("member is not backfilled: %+v", m.Member)
+	}
+}
+
+func TestUnmarshalMessageInteraction(t *testing.T) {
+	m := decodeMessage(t, interactionPayload)
+	if m.Type != 20 || m.ApplicationId != "1000000000000000001" || m.Flags != 64 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if m.Member != nil {
+		t.Errorf("bot response has a member %+v", m.Member)
+	}
+	in := m.Interaction
+	if in == nil || in.Id != "1290400000000000040" || in.Type != 2 || in.Name != "ping" || in.User.Id != "80351110224678912" {
+		t.Fatalf("unexpected interaction %+v", in)
+	}
+	if in.Member == nil || len(in.Member.Roles) != 1 {
+		t.Errorf("unexpected interaction member %+v", in.Member)
+	}
+	if len(m.Components) != 2 {
+		t.Fatalf("expected 2 action rows, got %d", len(m.Components))
+	}
+	row := m.Components[0]
+	if row.Type != 1 || len(row.Components) != 2 {
+		t.Fatalf("unexpected action row %+v", row)
+	}
+	again, docs := row.Components[0], row.Components[1]
+	if again.Type != 2 || again.Style != 1 || again.CustomId != "again" || again.Emoji == nil || again.Emoji.Name != "🔁" {
+		t.Errorf("unexpected button %+v", again)
+	}
+	if docs.Style != 5 || docs.URL != "https://discord.com/developers/docs" || !docs.Disabled || docs.CustomId != "" {
+		t.Errorf("unexpected link button %+v", docs)
+	}
+	menu := m.Components[1].Components[0]
+	if menu.Type != 3 || menu.CustomId != "pick" || menu.Placeholder != "Pick one" {
+		t.Fatalf("unexpected select menu %+v", menu)
+	}
+	if menu.MinValues == nil || *menu.MinValues != 0 || menu.MaxValues == nil || *menu.MaxValues != 1 {
+		t.Errorf("unexpected select menu bounds %v %v", menu.MinValues, menu.MaxValues)
+	}
+	if len(menu.Options) != 2 || !menu.Options[0].Default || menu.Options[1].Value != "2" {
+		t.Errorf("unexpected select menu options %+v", menu.Options)
+	}
+}
+
+func TestUnmarshalMessageSticker(t *testing.T) {
+	m := decodeMessage(t, stickerPayload)
+	if len(m.Stickers) != 1 {
+		t.Fatalf("expected 1 sticker, got %d", len(m.Stickers))
+	}
+	if s := m.Stickers[0]; s.Id != "749054660769218631" || s.Name != "Wave" || s.FormatType != 3 {
+		t.Errorf("unexpected sticker %+v", s)
+	}
+	if len(m.Attachments) != 0 || len(m.Reactions) != 0 || len(m.Components) != 0 {
+		t.Errorf("unexpected message %+v", m)
+	}
+	if m.Member == nil || m.Member.User.Id != "53908232506183680" || m.Member.GuildId != "1100000000000000001" {
+		t.Errorf("member is not backfilled: %+v", m.Member)
+	}
+}
+
+func TestUnmarshalMessageComponentsV2(t *testing.T) {
+	m := decodeMessage(t, componentsV2Payload)
+	if m.Flags != 32768 || len(m.Components) != 1 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	container := m.Components[0]
+	if container.Type != 17 || container.Id != 1 || container.AccentColor == nil || *container.AccentColor != 5793266 {
+		t.Fatalf("unexpected container %+v", container)
+	}
+	if len(container.Components) != 5 {
+		t.Fatalf("expected 5 container children, got %d", len(container.Components))
+	}
+	section := container.Components[0]
+	if section.Type != 9 || len(section.Components) != 1 || section.Components[0].Content != "## Weekly report" {
+		t.Errorf("unexpected section %+v", section)
+	}
+	thumbnail := section.Accessory
+	if thumbnail == nil || thumbnail.Type != 11 || thumbnail.Media == nil || thumbnail.Media.Width != 128 || thumbnail.Description != "icon" {
+		t.Errorf("unexpected thumbnail %+v", thumbnail)
+	}
+	separator := container.Components[1]
+	if separator.Type != 14 || separator.Divider == nil || *separator.Divider || separator.Spacing != 2 {
+		t.Errorf("unexpected separator %+v", separator)
+	}
+	gallery := container.Components[2]
+	if gallery.Type != 12 || len(gallery.Items) != 1 || !gallery.Items[0].Spoiler || gallery.Items[0].Description != "chart" {
+		t.Errorf("unexpected gallery %+v", gallery)
+	}
+	file := container.Components[3]
+	if file.Type != 13 || file.File == nil || file.File.URL != "attachment://report.pdf" {
+		t.Errorf("unexpected file %+v", file)
+	}
+	menu := container.Components[4].Components[0]
+	if menu.Type != 8 || len(menu.ChannelTypes) != 2 || menu.ChannelTypes[1] != 5 {
+		t.Errorf("unexpected channel select %+v", menu)
+	}
+	if len(menu.DefaultValues) != 1 || menu.DefaultValues[0].Type != "channel" {
+		t.Errorf("unexpected default values %+v", menu.DefaultValues)
+	}
+	if len(m.Attachments) != 1 || m.Attachments[0].Filename != "report.pdf" {
+		t.Errorf("unexpected attachments %+v", m.Attachments)
+	}
+	if m.Member != nil {
+		t.Errorf("bot message has a member %+v", m.Member)
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	payloads := map[string]string{
+		"reply":         replyPayload,
+		"crosspost":     crosspostPayload,
+		"forward":       forwardPayload,
+		"interaction":   interactionPayload,
+		"sticker":       stickerPayload,
+		"components v2": componentsV2Payload,
+	}
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			m := decodeMessage(t, payload)
+			if again := UnmarshalMessage(*m); !reflect.DeepEqual(again, m) {
+				t.Errorf("round trip changed the message\nbefore %+v\nafter  %+v", m, again)
+			}
+		})
+	}
+}
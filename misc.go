@@ -15,9 +15,19 @@ func AssignId(id string) string {
 	return id
 }
 
-func ScheduleDeletion(timeout float64, loc map[string]interface{}, ids map[string]bool) {
+func ScheduleDeletion(timeout float64, callbacks *Callbacks, ids map[string]bool) {
 	time.Sleep(time.Duration(timeout) * time.Second)
 	for id := range ids {
-		delete(loc, id)
+		callbacks.remove(id)
 	}
 }
+
+// Bool returns a pointer to v, used for optional fields
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Perms returns a pointer to v, used for optional permission fields
+func Perms(v Permissions) *Permissions {
+	return &v
+}
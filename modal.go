@@ -1,7 +1,12 @@
 package disgo
 
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
 type TextInput struct {
-	CustomId    string `json:"custom_id"`   // filled internally
+	CustomId    string `json:"custom_id"`   // default: random, set it to read the value with Context.ModalValue
 	Label       string `json:"label"`       // required default: "Text Input"
 	Style       int    `json:"style"`       // 1 for short, 2 for long default: 1
 	Value       string `json:"value"`       // default: ""
@@ -52,17 +57,36 @@ type Modal struct {
 	CustomId    string // filled internally
 	Fields      []TextInput
 	SelectMenus []SelectMenu
+	onSubmit    func(bot BotUser, ctx Context)
+	view        *View // the submit handler is removed along with the view
 }
 
 func (m *Modal) OnSubmit(handler func(bot BotUser, ctx Context)) {
 	m.CustomId = AssignId(m.CustomId)
-	callbackTasks[m.CustomId] = handler
+	m.onSubmit = handler
+}
+
+// Marshal builds the modal response, registering the submit handler in callbacks
+func (m *Modal) Marshal(callbacks *Callbacks) map[string]interface{} {
+	return m.marshal(callbacks)
 }
 
-func (m *Modal) Marshal() map[string]interface{} {
+func (m *Modal) marshal(callbacks *Callbacks) map[string]interface{} {
+	if m.Title == "" || utf8.RuneCountInString(m.Title) > 45 {
+		panic("Modal {title} must be between 1 and 45 characters")
+	}
+	if rows := len(m.Fields) + len(m.SelectMenus); rows < 1 || rows > 5 {
+		panic(fmt.Sprintf("Modal (%s) must have between 1 and 5 rows, got %d", m.Title, rows))
+	}
+	m.CustomId = AssignId(m.CustomId)
+	if m.onSubmit != nil && m.view != nil && m.view.state != nil {
+		m.view.state.own(m.CustomId, handler{fn: m.onSubmit})
+	} else if m.onSubmit != nil {
+		callbacks.register(m.CustomId, handler{fn: m.onSubmit}, nil)
+	}
 	modal := map[string]interface{}{}
 	modal["title"] = m.Title
-	modal["custom_id"] = AssignId(m.CustomId)
+	modal["custom_id"] = m.CustomId
 	modal["components"] = []map[string]interface{}{}
 	if len(m.Fields) > 0 {
 		for _, field := range m.Fields {
@@ -80,7 +104,7 @@ func (m *Modal) Marshal() map[string]interface{} {
 				"type":       1,
 				"components": []map[string]interface{}{},
 			}
-			row["components"] = append(row["components"].([]map[string]interface{}), menu.ToComponent())
+			row["components"] = append(row["components"].([]map[string]interface{}), menu.marshal(callbacks, nil))
 			modal["components"] = append(modal["components"].([]map[string]interface{}), row)
 		}
 	}
@@ -0,0 +1,148 @@
+package disgo
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Paginator is a prebuilt view to browse a list of embeds,
+// only the user who sent it can turn the pages
+type Paginator struct {
+	Pages     []Embed
+	PageFunc  func(page int) Embed // used when Pages is empty, pages start at 0
+	PageCount int                  // number of pages produced by PageFunc
+	Timeout   float64              // default: 3 * 60 seconds
+	Ephemeral bool
+	mu        sync.Mutex
+	page      int
+	id        string
+	view      *View
+}
+
+func (p *Paginator) count() int {
+	if len(p.Pages) > 0 {
+		return len(p.Pages)
+	}
+	return p.PageCount
+}
+
+func (p *Paginator) current() Embed {
+	if len(p.Pages) > 0 {
+		return p.Pages[p.page]
+	}
+	return p.PageFunc(p.page)
+}
+
+// turn moves to the page returned by to and updates the message of the interaction,
+// late interactions of a stopped paginator are only acknowledged
+func (p *Paginator) turn(ctx Context, to func(page int) int) {
+	if p.view.state.isStopped() {
+		ctx.Defer(false)
+		return
+	}
+	p.mu.Lock()
+	page := to(p.page)
+	if page < 0 {
+		page = 0
+	}
+	if last := p.count() - 1; page > last {
+		page = last
+	}
+	p.page = page
+	resp := p.render()
+	p.mu.Unlock()
+	ctx.Edit(resp)
+}
+
+func (p *Paginator) render() Response {
+	last := p.count() - 1
+	p.view.ActionRows = []ActionRow{{Buttons: []Button{
+		{
+			Style:    2,
+			Label:    "«",
+			CustomId: p.id + ":first",
+			Disabled: p.page == 0,
+			OnClick:  func(bot BotUser, ctx Context) { p.turn(ctx, func(int) int { return 0 }) },
+		},
+		{
+			Style:    1,
+			Label:    "‹",
+			CustomId: p.id + ":prev",
+			Disabled: p.page == 0,
+			OnClick:  func(bot BotUser, ctx Context) { p.turn(ctx, func(page int) int { return page - 1 }) },
+		},
+		{
+			Style:    2,
+			Label:    fmt.Sprintf("%d/%d", p.page+1, last+1),
+			CustomId: p.id + ":jump",
+			Disabled: last == 0,
+			OnClick:  func(bot BotUser, ctx Context) { ctx.SendModal(p.jumpModal()) },
+		},
+		{
+			Style:    1,
+			Label:    "›",
+			CustomId: p.id + ":next",
+			Disabled: p.page == last,
+			OnClick:  func(bot BotUser, ctx Context) { p.turn(ctx, func(page int) int { return page + 1 }) },
+		},
+		{
+			Style:    2,
+			Label:    "»",
+			CustomId: p.id + ":last",
+			Disabled: p.page == last,
+			OnClick:  func(bot BotUser, ctx Context) { p.turn(ctx, func(int) int { return p.count() - 1 }) },
+		},
+	}}}
+	return Response{Embed: p.current(), View: *p.view, Ephemeral: p.Ephemeral}
+}
+
+func (p *Paginator) jumpModal() Modal {
+	modal := Modal{
+		Title:    "Jump to page",
+		CustomId: p.id + ":modal",
+		Fields: []TextInput{{
+			CustomId:    "page",
+			Label:       fmt.Sprintf("Page (1-%d)", p.count()),
+			Placeholder: strconv.Itoa(p.page + 1),
+			MinLength:   1,
+			MaxLength:   len(strconv.Itoa(p.count())),
+			Required:    true,
+		}},
+		view: p.view,
+	}
+	modal.OnSubmit(func(bot BotUser, ctx Context) {
+		page, err := strconv.Atoi(ctx.ModalValue("page"))
+		if err != nil {
+			ctx.Send(Response{Content: "Page must be a number", Ephemeral: true})
+			return
+		}
+		p.turn(ctx, func(int) int { return page - 1 })
+	})
+	return modal
+}
+
+// Send responds to the interaction with the first page
+func (p *Paginator) Send(ctx Context) {
+	if p.count() == 0 || len(p.Pages) == 0 && p.PageFunc == nil {
+		panic("Paginator must have {pages} or a {page func} with a {page count}")
+	}
+	p.mu.Lock()
+	if p.Timeout <= 0 {
+		p.Timeout = 3 * 60
+	}
+	p.id = AssignId("")
+	p.view = NewView(p.Timeout)
+	p.view.AllowedUsers = []Snowflake{ctx.Author().Id}
+	p.view.DisableComponents = true
+	resp := p.render()
+	p.mu.Unlock()
+	ctx.Send(resp)
+}
+
+// Stop ends the paginator and disables its buttons
+func (p *Paginator) Stop() {
+	if p.view != nil {
+		p.view.Stop()
+	}
+}
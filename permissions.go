@@ -0,0 +1,174 @@
+package disgo
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Permissions is a bitset of Discord permissions, sent as a string by the API
+type Permissions int64
+
+const (
+	CreateInstantInvitePermission              Permissions = 1 << 0
+	KickMembersPermission                      Permissions = 1 << 1
+	BanMembersPermission                       Permissions = 1 << 2
+	AdministratorPermission                    Permissions = 1 << 3
+	ManageChannelsPermission                   Permissions = 1 << 4
+	ManageGuildPermission                      Permissions = 1 << 5
+	AddReactionsPermission                     Permissions = 1 << 6
+	ViewAuditLogPermission                     Permissions = 1 << 7
+	PrioritySpeakerPermission                  Permissions = 1 << 8
+	StreamPermission                           Permissions = 1 << 9
+	ViewChannelPermission                      Permissions = 1 << 10
+	SendMessagesPermission                     Permissions = 1 << 11
+	SendTTSMessagesPermission                  Permissions = 1 << 12
+	ManageMessagesPermission                   Permissions = 1 << 13
+	EmbedLinksPermission                       Permissions = 1 << 14
+	AttachFilesPermission                      Permissions = 1 << 15
+	ReadMessageHistoryPermission               Permissions = 1 << 16
+	MentionEveryonePermission                  Permissions = 1 << 17
+	UseExternalEmojisPermission                Permissions = 1 << 18
+	ViewGuildInsightsPermission                Permissions = 1 << 19
+	ConnectPermission                          Permissions = 1 << 20
+	SpeakPermission                            Permissions = 1 << 21
+	MuteMembersPermission                      Permissions = 1 << 22
+	DeafenMembersPermission                    Permissions = 1 << 23
+	MoveMembersPermission                      Permissions = 1 << 24
+	UseVADPermission                           Permissions = 1 << 25
+	ChangeNicknamePermission                   Permissions = 1 << 26
+	ManageNicknamesPermission                  Permissions = 1 << 27
+	ManageRolesPermission                      Permissions = 1 << 28
+	ManageWebhooksPermission                   Permissions = 1 << 29
+	ManageGuildExpressionsPermission           Permissions = 1 << 30
+	UseApplicationCommandsPermission           Permissions = 1 << 31
+	RequestToSpeakPermission                   Permissions = 1 << 32
+	ManageEventsPermission                     Permissions = 1 << 33
+	ManageThreadsPermission                    Permissions = 1 << 34
+	CreatePublicThreadsPermission              Permissions = 1 << 35
+	CreatePrivateThreadsPermission             Permissions = 1 << 36
+	UseExternalStickersPermission              Permissions = 1 << 37
+	SendMessagesInThreadsPermission            Permissions = 1 << 38
+	UseEmbeddedActivitiesPermission            Permissions = 1 << 39
+	ModerateMembersPermission                  Permissions = 1 << 40
+	ViewCreatorMonetizationAnalyticsPermission Permissions = 1 << 41
+	UseSoundboardPermission                    Permissions = 1 << 42
+	CreateGuildExpressionsPermission           Permissions = 1 << 43
+	CreateEventsPermission                     Permissions = 1 << 44
+	UseExternalSoundsPermission                Permissions = 1 << 45
+	SendVoiceMessagesPermission                Permissions = 1 << 46
+	SendPollsPermission                        Permissions = 1 << 49
+	UseExternalAppsPermission                  Permissions = 1 << 50
+
+	AllPermissions Permissions = 1<<47 - 1 | SendPollsPermission | UseExternalAppsPermission
+)
+
+// Has reports whether every bit of perms is set
+func (p Permissions) Has(perms Permissions) bool {
+	return p&perms == perms
+}
+
+func (p Permissions) Add(perms Permissions) Permissions {
+	return p | perms
+}
+
+func (p Permissions) Remove(perms Permissions) Permissions {
+	return p &^ perms
+}
+
+func (p Permissions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(p), 10))
+}
+
+// UnmarshalJSON accepts the bitset as a string or a number
+func (p *Permissions) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int64
+		if err = json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*p = Permissions(n)
+		return nil
+	}
+	if s == "" {
+		*p = 0
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	*p = Permissions(n)
+	return err
+}
+
+// PermissionOverwrite allows or denies permissions of a role or member in a channel
+type PermissionOverwrite struct {
+	Id    Snowflake   `json:"id"`
+	Type  int         `json:"type"` // 0 (role), 1 (member)
+	Allow Permissions `json:"allow"`
+	Deny  Permissions `json:"deny"`
+}
+
+// timedOut reports whether the member is timed out at the moment
+func (member *Member) timedOut() bool {
+	if member.TimeoutExpiry == "" {
+		return false
+	}
+	until, err := time.Parse(time.RFC3339, member.TimeoutExpiry)
+	return err == nil && until.After(time.Now())
+}
+
+// BasePermissions computes the guild wide permissions of a member
+// from the everyone role and the roles of the member
+func (guild *Guild) BasePermissions(member Member) Permissions {
+	if guild.OwnerID != "" && guild.OwnerID == member.User.Id {
+		return AllPermissions
+	}
+	perms := guild.Roles[guild.Id].Permissions
+	for _, id := range member.Roles {
+		perms |= guild.Roles[id].Permissions
+	}
+	if perms.Has(AdministratorPermission) {
+		return AllPermissions
+	}
+	if member.timedOut() {
+		perms &= ViewChannelPermission | ReadMessageHistoryPermission
+	}
+	return perms
+}
+
+// ChannelPermissions computes the permissions of a member in a channel, applying the
+// everyone, role and member overwrites on top of the guild wide permissions
+func (guild *Guild) ChannelPermissions(member Member, channel Channel) Permissions {
+	perms := guild.BasePermissions(member)
+	if perms == AllPermissions {
+		return perms
+	}
+	var allow, deny Permissions
+	var memberOverwrite *PermissionOverwrite
+	for i, o := range channel.Overwrites {
+		switch {
+		case o.Type == 0 && o.Id == guild.Id:
+			perms = perms&^o.Deny | o.Allow
+		case o.Type == 0:
+			for _, id := range member.Roles {
+				if id == o.Id {
+					allow, deny = allow|o.Allow, deny|o.Deny
+					break
+				}
+			}
+		case o.Type == 1 && o.Id == member.User.Id:
+			memberOverwrite = &channel.Overwrites[i]
+		}
+	}
+	perms = perms&^deny | allow
+	if memberOverwrite != nil {
+		perms = perms&^memberOverwrite.Deny | memberOverwrite.Allow
+	}
+	if member.timedOut() {
+		perms &= ViewChannelPermission | ReadMessageHistoryPermission
+	}
+	if !perms.Has(ViewChannelPermission) {
+		return 0
+	}
+	return perms
+}
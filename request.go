@@ -7,6 +7,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 )
 
 const BASE = "https://discord.com/api/v10"
@@ -50,18 +51,27 @@ type MinimalRouter struct {
 }
 
 func (obj *MinimalRouter) Request() *http.Response {
-	body, _ := json.Marshal(obj.Data)
-	r, _ := http.NewRequest(obj.Method, BASE+obj.Path, io.NopCloser(bytes.NewBuffer(body)))
-	r.Header.Set(`Content-Type`, `application/json`)
-	r.Header.Set(`Authorization`, fmt.Sprintf(`Bot %s`, obj.Token))
-	client := &http.Client{}
-	resp, err := client.Do(r)
+	resp, err := obj.Do()
 	if err != nil {
 		log.Println(err)
 	}
 	return resp
 }
 
+// Do sends the request, returning the transport error instead of logging it
+func (obj *MinimalRouter) Do() (*http.Response, error) {
+	body, _ := json.Marshal(obj.Data)
+	r, _ := http.NewRequest(obj.Method, BASE+obj.Path, io.NopCloser(bytes.NewBuffer(body)))
+	r.Header.Set(`Content-Type`, `application/json`)
+	if strings.HasPrefix(obj.Token, "Bearer ") {
+		r.Header.Set(`Authorization`, obj.Token)
+	} else {
+		r.Header.Set(`Authorization`, fmt.Sprintf(`Bot %s`, obj.Token))
+	}
+	client := &http.Client{}
+	return client.Do(r)
+}
+
 func MinimalReq(method string, path string, data map[string]interface{}, token string) *MinimalRouter {
 	return &MinimalRouter{Method: method, Path: path, Data: data, Token: token}
 }
@@ -3,18 +3,18 @@ package disgo
 import "encoding/json"
 
 type Role struct {
-	Id           string `json:"id"`
-	Name         string `json:"name"`
-	Color        int    `json:"color"`
-	Hoist        bool   `json:"hoist"`
-	Icon         string `json:"icon"`
-	UnicodeEmoji bool   `json:"unicode_emoji"`
-	Position     int    `json:"position"`
-	Permissions  string `json:"permissions"`
-	Managed      bool   `json:"managed"`
-	Mentionable  bool   `json:"mentionable"`
-	Tags         string `json:"tags"`
-	GuildId      string `json:"guild_id"`
+	Id           Snowflake   `json:"id"`
+	Name         string      `json:"name"`
+	Color        int         `json:"color"`
+	Hoist        bool        `json:"hoist"`
+	Icon         string      `json:"icon"`
+	UnicodeEmoji bool        `json:"unicode_emoji"`
+	Position     int         `json:"position"`
+	Permissions  Permissions `json:"permissions"`
+	Managed      bool        `json:"managed"`
+	Mentionable  bool        `json:"mentionable"`
+	Tags         string      `json:"tags"`
+	GuildId      Snowflake   `json:"guild_id"`
 }
 
 func DataToRole(payload interface{}) *Role {
@@ -23,3 +23,55 @@ func DataToRole(payload interface{}) *Role {
 	_ = json.Unmarshal(data, role)
 	return role
 }
+
+// Compare orders roles in the guild hierarchy, returning 1 when the role is above other,
+// -1 when below and 0 for the same role; roles sharing a position are ordered by id,
+// the older role ranking higher
+func (role Role) Compare(other Role) int {
+	switch {
+	case role.Position > other.Position:
+		return 1
+	case role.Position < other.Position:
+		return -1
+	case role.Id.Uint64() < other.Id.Uint64():
+		return 1
+	case role.Id.Uint64() > other.Id.Uint64():
+		return -1
+	}
+	return 0
+}
+
+// TopRole returns the highest role of a member, the everyone role when the member has none
+func (guild *Guild) TopRole(member Member) Role {
+	top := guild.Roles[guild.Id]
+	for _, id := range member.Roles {
+		if r, ok := guild.Roles[id]; ok && r.Compare(top) > 0 {
+			top = r
+		}
+	}
+	return top
+}
+
+// ColorRole returns the highest role of a member having a color
+func (guild *Guild) ColorRole(member Member) (Role, bool) {
+	var top Role
+	found := false
+	for _, id := range member.Roles {
+		if r, ok := guild.Roles[id]; ok && r.Color != 0 && (!found || r.Compare(top) > 0) {
+			top, found = r, true
+		}
+	}
+	return top, found
+}
+
+// CanModerate reports whether the actor outranks the target, the owner
+// outranks everyone and can not be moderated, nobody can moderate themselves
+func (guild *Guild) CanModerate(actor Member, target Member) bool {
+	if actor.User.Id == target.User.Id || target.User.Id == guild.OwnerID {
+		return false
+	}
+	if actor.User.Id == guild.OwnerID {
+		return true
+	}
+	return guild.TopRole(actor).Compare(guild.TopRole(target)) > 0
+}
@@ -0,0 +1,79 @@
+package disgo
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var routeParam = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+// ComponentHandler is a persistent handler of every button, select menu or modal
+// whose custom id matches Pattern, e.g. "ticket:close:{id}"
+type ComponentHandler struct {
+	Pattern  string
+	Handler  func(bot BotUser, ctx Context, params map[string]string)
+	Checks   []Check
+	Cooldown *Cooldown
+}
+
+// componentRoute maps custom ids like "ticket:close:{id}" to a persistent handler
+type componentRoute struct {
+	pattern string
+	re      *regexp.Regexp
+	names   []string
+	handler func(bot BotUser, ctx Context, params map[string]string)
+	guard   guard
+}
+
+func newComponentRoute(h ComponentHandler) componentRoute {
+	pattern := h.Pattern
+	if pattern == "" || len(pattern) > 100 {
+		panic("Component route {pattern} must be between 1 and 100 characters")
+	}
+	var names []string
+	var expr strings.Builder
+	last := 0
+	for _, loc := range routeParam.FindAllStringSubmatchIndex(pattern, -1) {
+		expr.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		expr.WriteString(`([^:]+)`)
+		names = append(names, pattern[loc[2]:loc[3]])
+		last = loc[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(pattern[last:]))
+	re, err := regexp.Compile(fmt.Sprintf("^%s$", expr.String()))
+	if err != nil {
+		panic(fmt.Sprintf("Invalid component route (%s): %v", pattern, err))
+	}
+	if h.Handler == nil {
+		panic(fmt.Sprintf("Component route (%s) must have a {handler}", pattern))
+	}
+	return componentRoute{
+		pattern: pattern,
+		re:      re,
+		names:   names,
+		handler: h.Handler,
+		guard:   guard{checks: h.Checks, cooldown: h.Cooldown},
+	}
+}
+
+func (r componentRoute) match(customId string) (map[string]string, bool) {
+	groups := r.re.FindStringSubmatch(customId)
+	if groups == nil {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, name := range r.names {
+		params[name] = groups[i+1]
+	}
+	return params, true
+}
+
+// RouteId fills the parameters of a route pattern to build a custom id,
+// e.g. RouteId("ticket:close:{id}", "id", "42") returns "ticket:close:42"
+func RouteId(pattern string, pairs ...string) string {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		pattern = strings.ReplaceAll(pattern, fmt.Sprintf("{%s}", pairs[i]), pairs[i+1])
+	}
+	return pattern
+}
@@ -0,0 +1,184 @@
+package disgo
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type interactionReply struct {
+	body  map[string]interface{}
+	files []File
+}
+
+// inlineResponder hands the first response of an HTTP interaction
+// back to the request that delivered it
+type inlineResponder struct {
+	mu      sync.Mutex
+	done    bool
+	ack     int // type of the deferred acknowledgement sent in place of a slow reply
+	replies chan interactionReply
+}
+
+func (r *inlineResponder) respond(body map[string]interface{}, files []File) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return false
+	}
+	r.done = true
+	r.replies <- interactionReply{body: body, files: files}
+	return true
+}
+
+// acknowledge defers the interaction unless the reply was already handed over
+func (r *inlineResponder) acknowledge(ack int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return false
+	}
+	r.done, r.ack = true, ack
+	return true
+}
+
+func (r *inlineResponder) deferred() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ack
+}
+
+// deferredAck is the acknowledgement of an interaction whose handler misses the deadline,
+// components keep their message while commands and modals show a loading state
+func deferredAck(interactionType int) map[string]interface{} {
+	switch interactionType {
+	case 3:
+		return map[string]interface{}{"type": 6}
+	case 4:
+		return map[string]interface{}{"type": 8, "data": map[string]interface{}{"choices": []interface{}{}}}
+	}
+	return map[string]interface{}{"type": 5}
+}
+
+func writeReply(w http.ResponseWriter, reply interactionReply) {
+	if len(reply.files) > 0 {
+		payload, boundary := MultiPartWriter(reply.body, reply.files)
+		w.Header().Set("Content-Type", fmt.Sprintf("multipart/form-data; boundary=%s", boundary))
+		_, _ = w.Write(payload)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(reply.body)
+}
+
+// interactionServer is an http.Handler receiving interactions
+// from discord instead of the gateway
+type interactionServer struct {
+	sock      *Socket
+	publicKey ed25519.PublicKey
+}
+
+func (sock *Socket) setupHTTP(token string) {
+	sock.commandHooks = make(map[Snowflake]interface{})
+	sock.guards = make(map[Snowflake]guard)
+	var app struct {
+		Id Snowflake `json:"id"`
+	}
+	sock.self = &BotUser{}
+	if err := sock.self.fetch("GET", "/users/@me", nil, token, sock.self); err != nil {
+		log.Println(err)
+	}
+	if err := sock.self.fetch("GET", "/oauth2/applications/@me", nil, token, &app); err != nil {
+		log.Println(err)
+	}
+	sock.self.token = token
+	sock.self.applicationId = app.Id
+	sock.self.IsReady = true
+	if sock.state == nil {
+		sock.state = newState()
+	}
+	sock.self.State = sock.state
+	for _, cmd := range sock.queue {
+		go sock.registerCommand(cmd, token, app.Id)
+	}
+}
+
+func (s *interactionServer) verify(r *http.Request, body []byte) bool {
+	sig, err := hex.DecodeString(r.Header.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	msg := append([]byte(r.Header.Get("X-Signature-Timestamp")), body...)
+	return ed25519.Verify(s.publicKey, msg, sig)
+}
+
+func (s *interactionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil || !s.verify(r, body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	var data map[string]interface{}
+	if err = json.Unmarshal(body, &data); err != nil {
+		http.Error(w, "invalid interaction payload", http.StatusBadRequest)
+		return
+	}
+	ctx := UnmarshalContext(data)
+	ctx.inline = &inlineResponder{replies: make(chan interactionReply, 1)}
+	dispatched := make(chan bool, 1)
+	go func() {
+		dispatched <- s.sock.dispatchInteraction(ctx, data)
+	}()
+	timeout := time.After(2500 * time.Millisecond)
+	for {
+		select {
+		case reply := <-ctx.inline.replies:
+			writeReply(w, reply)
+			return
+		case ok := <-dispatched:
+			// nothing would answer an unknown command or component,
+			// e.g. one registered before a redeploy, so it fails right away
+			if !ok {
+				http.Error(w, "no handler for the interaction", http.StatusNotFound)
+				return
+			}
+			dispatched = nil
+		case <-timeout:
+			// discord drops the interaction after 3 seconds, later responses
+			// of the handler go to the followup and @original endpoints
+			ack := deferredAck(ctx.Type)
+			if ctx.inline.acknowledge(ack["type"].(int)) {
+				writeReply(w, interactionReply{body: ack})
+			} else {
+				writeReply(w, <-ctx.inline.replies)
+			}
+			return
+		}
+	}
+}
+
+// Handler returns an http.Handler for the interactions endpoint url of the application,
+// requests are verified using the hex encoded public key of the application
+func (con *connection) Handler(token string, publicKey string) http.Handler {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		panic("Application {public key} must be a hex encoded ed25519 key")
+	}
+	con.sock.setupHTTP(token)
+	return &interactionServer{sock: con.sock, publicKey: key}
+}
+
+// RunHTTP serves the interactions endpoint on addr instead of connecting to the gateway
+func (con *connection) RunHTTP(token string, publicKey string, addr string) {
+	log.Println(http.ListenAndServe(addr, con.Handler(token, publicKey)))
+}
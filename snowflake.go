@@ -0,0 +1,80 @@
+package disgo
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// DiscordEpoch is the first millisecond of 2015, the epoch of snowflake timestamps
+const DiscordEpoch = 1420070400000
+
+// Snowflake is a Discord id, it marshals as a JSON string
+// and unmarshals from both a string and a number
+type Snowflake string
+
+// SnowflakeFromTime creates the smallest snowflake of the given time,
+// useful as the before or after bound when paginating
+func SnowflakeFromTime(t time.Time) Snowflake {
+	ms := t.UnixMilli() - DiscordEpoch
+	if ms < 0 {
+		ms = 0
+	}
+	return Snowflake(strconv.FormatUint(uint64(ms)<<22, 10))
+}
+
+func (s *Snowflake) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*s = Snowflake(id)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*s = Snowflake(n)
+	return nil
+}
+
+func (s Snowflake) String() string {
+	return string(s)
+}
+
+// Uint64 returns the numeric value, 0 for an empty or malformed snowflake
+func (s Snowflake) Uint64() uint64 {
+	n, _ := strconv.ParseUint(string(s), 10, 64)
+	return n
+}
+
+// Time returns the creation time encoded in the snowflake
+func (s Snowflake) Time() time.Time {
+	return time.UnixMilli(int64(s.Uint64()>>22) + DiscordEpoch)
+}
+
+func (s Snowflake) WorkerId() int {
+	return int(s.Uint64() >> 17 & 0x1f)
+}
+
+func (s Snowflake) ProcessId() int {
+	return int(s.Uint64() >> 12 & 0x1f)
+}
+
+// Increment is the sequence number of the snowflake within its millisecond
+func (s Snowflake) Increment() int {
+	return int(s.Uint64() & 0xfff)
+}
+
+// snowflakeOf reads an id of a gateway payload
+func snowflakeOf(v interface{}) Snowflake {
+	switch id := v.(type) {
+	case string:
+		return Snowflake(id)
+	case float64:
+		return Snowflake(strconv.FormatFloat(id, 'f', -1, 64))
+	}
+	return ""
+}
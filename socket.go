@@ -7,6 +7,8 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,15 +20,26 @@ type Socket struct {
 	Intent       int
 	Memoize      bool
 	Presence     Presence
+	Catalog      Catalog
+	Callbacks    CallbackStore
+	CheckFailure func(bot BotUser, ctx Context, err error)
 	interval     float64
 	beatSent     int64
 	beatAck      int64
 	latency      int64
 	self         *BotUser
-	guilds       map[string]*Guild
+	state        *State
 	queue        []ApplicationCommand
 	eventHooks   map[string]interface{}
-	commandHooks map[string]interface{}
+	commandHooks map[Snowflake]interface{}
+	guards       map[Snowflake]guard
+	handlers     *Callbacks
+	routes       []componentRoute
+	mu           sync.Mutex
+	conn         *websocket.Conn
+	writeMu      sync.Mutex
+	chunks       map[string]*memberChunks
+	closed       int32
 	sequence     int
 	sessionId    string
 }
@@ -40,8 +53,8 @@ func (sock *Socket) getGateway() string {
 }
 
 func (sock *Socket) keepAlive(conn *websocket.Conn, dur int) {
-	for {
-		_ = conn.WriteJSON(map[string]interface{}{"op": 1, "d": 251})
+	for atomic.LoadInt32(&sock.closed) == 0 {
+		_ = sock.write(conn, map[string]interface{}{"op": 1, "d": 251})
 		sock.beatSent = time.Now().UnixMilli()
 		time.Sleep(time.Duration(dur) * time.Millisecond)
 	}
@@ -75,7 +88,15 @@ func (sock *Socket) identify(conn *websocket.Conn, Token string, intent int) {
 		d.Properties.Browser = "Discord iOS"
 	}
 	payload := map[string]interface{}{"op": 2, "d": d}
-	_ = conn.WriteJSON(payload)
+	_ = sock.write(conn, payload)
+}
+
+// write sends a payload to the gateway, heartbeats are sent
+// from another goroutine so writes must not interleave
+func (sock *Socket) write(conn *websocket.Conn, payload interface{}) error {
+	sock.writeMu.Lock()
+	defer sock.writeMu.Unlock()
+	return conn.WriteJSON(payload)
 }
 
 func (sock *Socket) AddHandler(name string, handler interface{}) {
@@ -91,11 +112,11 @@ func (sock *Socket) AddToQueue(commands ...ApplicationCommand) {
 	}
 }
 
-func (sock *Socket) registerCommand(com ApplicationCommand, token string, applicationId string) {
+func (sock *Socket) registerCommand(com ApplicationCommand, token string, applicationId Snowflake) {
 	var route string
 	data, hook, guildId := com.Marshal()
-	if guildId != 0 {
-		route = fmt.Sprintf("/applications/%s/guilds/%v/commands", applicationId, guildId)
+	if guildId != "" {
+		route = fmt.Sprintf("/applications/%s/guilds/%s/commands", applicationId, guildId)
 	} else {
 		route = fmt.Sprintf("/applications/%s/commands", applicationId)
 	}
@@ -105,7 +126,11 @@ func (sock *Socket) registerCommand(com ApplicationCommand, token string, applic
 	_ = json.Unmarshal(body, &d)
 	_, ok := d["id"]
 	if ok {
-		sock.commandHooks[d["id"].(string)] = hook
+		id := snowflakeOf(d["id"])
+		sock.mu.Lock()
+		sock.commandHooks[id] = hook
+		sock.guards[id] = guard{checks: com.Checks, cooldown: com.Cooldown}
+		sock.mu.Unlock()
 	} else {
 		log.Fatal(
 			fmt.Sprintf("Failed to register command {%s}. Reason: %s", com.Name, d["message"]))
@@ -113,13 +138,18 @@ func (sock *Socket) registerCommand(com ApplicationCommand, token string, applic
 }
 
 func (sock *Socket) Run(token string) {
-	sock.guilds = make(map[string]*Guild)
-	sock.commandHooks = make(map[string]interface{})
+	if sock.state == nil {
+		sock.state = newState()
+	}
+	sock.state.memoized = sock.Memoize
+	sock.commandHooks = make(map[Snowflake]interface{})
+	sock.guards = make(map[Snowflake]guard)
 	wss := sock.getGateway()
 	conn, _, err := websocket.DefaultDialer.Dial(wss, nil)
 	if err != nil {
 		log.Println(err)
 	}
+	sock.conn = conn
 	for {
 		var wsmsg struct {
 			Op       int                    `json:"op"`
@@ -127,13 +157,15 @@ func (sock *Socket) Run(token string) {
 			Sequence int                    `json:"s"`
 			Data     map[string]interface{} `json:"d"`
 		}
-		_ = conn.ReadJSON(&wsmsg)
+		if err = conn.ReadJSON(&wsmsg); err != nil && atomic.LoadInt32(&sock.closed) == 1 {
+			return
+		}
 		var runtime struct {
 			SessionId   string `json:"session_id"`
 			Sequence    int
 			Application struct {
-				Id    string  `json:"id"`
-				Flags float64 `json:"flags"`
+				Id    Snowflake `json:"id"`
+				Flags float64   `json:"flags"`
 			} `json:"application"`
 		}
 		sock.sequence = wsmsg.Sequence
@@ -144,6 +176,9 @@ func (sock *Socket) Run(token string) {
 				go sock.registerCommand(cmd, token, runtime.Application.Id)
 			}
 			sock.self = Unmarshal(wsmsg.Data["user"].(map[string]interface{}))
+			sock.self.token = token
+			sock.self.applicationId = runtime.Application.Id
+			sock.self.State = sock.state
 			sock.self.Latency = sock.latency
 			sock.self.IsReady = true
 			islocked = false
@@ -164,7 +199,7 @@ func (sock *Socket) Run(token string) {
 			}
 		}
 		if wsmsg.Op == 7 {
-			_ = conn.WriteJSON(map[string]interface{}{
+			_ = sock.write(conn, map[string]interface{}{
 				"op": 6,
 				"d": map[string]interface{}{
 					"token":      token,
@@ -176,29 +211,37 @@ func (sock *Socket) Run(token string) {
 		if wsmsg.Op == 9 {
 			sock.identify(conn, token, sock.Intent)
 		}
-		sock.eventHandler(wsmsg.Event, wsmsg.Data)
+		cached := sock.state.apply(wsmsg.Event, wsmsg.Data)
+		sock.eventHandler(wsmsg.Event, wsmsg.Data, cached)
+		if wsmsg.Event == "GUILD_MEMBERS_CHUNK" {
+			sock.collectChunk(wsmsg.Data)
+		}
 		if h, ok := sock.eventHooks[OnSocketReceive]; ok {
 			handler := h.(func(d map[string]interface{}))
 			go handler(wsmsg.Data)
 		}
-		if wsmsg.Event == "GUILD_CREATE" {
-			gld := UnmarshalGuild(wsmsg.Data)
-			gld.UnmarshalRoles(wsmsg.Data["roles"].([]interface{}))
-			gld.UnmarshalChannels(wsmsg.Data["channels"].([]interface{}))
-			sock.guilds[gld.Id] = gld
-			sock.self.Guilds = sock.guilds
-			if sock.Memoize {
-				requestMembers(conn, gld.Id)
-			}
-		}
-		if wsmsg.Event == "GUILD_MEMBERS_CHUNK" {
-			id := wsmsg.Data["guild_id"].(string)
-			sock.guilds[id].UnmarshalMembers(wsmsg.Data["members"].([]interface{}))
+		if wsmsg.Event == "GUILD_CREATE" && sock.Memoize && sock.state.Caches(CacheMembers) {
+			_ = sock.write(conn, map[string]interface{}{
+				"op": 8,
+				"d":  map[string]interface{}{"guild_id": wsmsg.Data["id"], "query": "", "limit": 0},
+			})
 		}
 	}
 }
 
-func (sock *Socket) eventHandler(event string, data map[string]interface{}) {
+// close stops the gateway connection and flushes the cache backend
+func (sock *Socket) close() error {
+	atomic.StoreInt32(&sock.closed, 1)
+	if sock.conn != nil {
+		_ = sock.conn.Close()
+	}
+	if sock.state != nil {
+		return sock.state.Close()
+	}
+	return nil
+}
+
+func (sock *Socket) eventHandler(event string, data map[string]interface{}, cached interface{}) {
 	if islocked {
 		return
 	}
@@ -210,6 +253,49 @@ func (sock *Socket) eventHandler(event string, data map[string]interface{}) {
 			go hook(*sock.self, *UnmarshalMessage(data))
 		}
 
+	case OnAppCmdPermsUpdate:
+		if event, ok := sock.eventHooks[event]; ok {
+			hook := event.(func(bot BotUser, perms GuildCommandPermissions))
+			go hook(*sock.self, *UnmarshalCommandPermissions(data))
+		}
+
+	case OnMessageUpdate:
+		if event, ok := sock.eventHooks[event]; ok {
+			hook := event.(func(bot BotUser, before *Message, after Message))
+			before, _ := cached.(*Message)
+			after := UnmarshalMessage(data)
+			if before != nil {
+				after = UnmarshalMessage(before)
+				merge(after, data)
+			}
+			go hook(*sock.self, before, *after)
+		}
+
+	case OnMessageDelete:
+		if event, ok := sock.eventHooks[event]; ok {
+			hook := event.(func(bot BotUser, message MessageDelete))
+			del := MessageDelete{}
+			del.Cached, _ = cached.(*Message)
+			del.Id = snowflakeOf(data["id"])
+			del.ChannelId = snowflakeOf(data["channel_id"])
+			del.GuildId = snowflakeOf(data["guild_id"])
+			go hook(*sock.self, del)
+		}
+
+	case OnMessageDeleteBulk:
+		if event, ok := sock.eventHooks[event]; ok {
+			hook := event.(func(bot BotUser, messages MessageBulkDelete))
+			del := MessageBulkDelete{}
+			del.Cached, _ = cached.([]Message)
+			ids, _ := data["ids"].([]interface{})
+			for _, id := range ids {
+				del.Ids = append(del.Ids, snowflakeOf(id))
+			}
+			del.ChannelId = snowflakeOf(data["channel_id"])
+			del.GuildId = snowflakeOf(data["guild_id"])
+			go hook(*sock.self, del)
+		}
+
 	case OnGuildCreate:
 		if event, ok := sock.eventHooks[event]; ok {
 			hook := event.(func(bot BotUser, guild Guild))
@@ -217,73 +303,149 @@ func (sock *Socket) eventHandler(event string, data map[string]interface{}) {
 		}
 
 	case OnInteractionCreate:
-		ctx := UnmarshalContext(data)
-		if event, ok := sock.eventHooks[event]; ok {
-			hook := event.(func(bot BotUser, ctx Context))
-			go hook(*sock.self, *ctx)
+		sock.dispatchInteraction(UnmarshalContext(data), data)
+	default:
+	}
+}
+
+// dispatchInteraction routes an interaction received over the gateway or the HTTP endpoint
+// to its handlers, reporting whether anything was dispatched that answers the interaction
+func (sock *Socket) dispatchInteraction(ctx *Context, data map[string]interface{}) bool {
+	ctx.catalog = sock.Catalog
+	ctx.handlers = sock.callbacks()
+	ctx.bot = sock.self
+	event, hooked := sock.eventHooks[OnInteractionCreate]
+	if hooked {
+		hook := event.(func(bot BotUser, ctx Context))
+		go hook(*sock.self, *ctx)
+	}
+	switch ctx.Type {
+	case 1:
+		ctx.callback(map[string]interface{}{"type": 1}, nil)
+		return true
+	case 2:
+		sock.mu.Lock()
+		ev, ok := sock.commandHooks[ctx.Data.Id]
+		g := sock.guards[ctx.Data.Id]
+		sock.mu.Unlock()
+		if !ok {
+			return hooked
 		}
-		switch ctx.Type {
-		case 1:
-			// interaction ping
-		case 2:
-			if ev, ok := sock.commandHooks[ctx.Data.Id]; ok {
-				hook := ev.(func(bot BotUser, ctx Context, ops ...SlashCommandOption))
+		if !sock.allow(g, ctx) {
+			return true
+		}
+		switch hook := ev.(type) {
+		case func(bot BotUser, ctx Context, ops ...SlashCommandOption):
+			if hook != nil {
 				go hook(*sock.self, *ctx, ctx.Data.Options...)
+				return true
 			}
-		case 3:
-			var compdata ComponentData
-			da, _ := json.Marshal(data["data"].(map[string]interface{}))
-			_ = json.Unmarshal(da, &compdata)
-			ctx.ComponentData = compdata
-			switch ctx.ComponentData.ComponentType {
-			case 2:
-				cb, ok := callbackTasks[ctx.ComponentData.CustomId]
-				if ok {
-					callback := cb.(func(b BotUser, ctx Context))
-					go callback(*sock.self, *ctx)
-				}
-			case 3:
-				cb, ok := callbackTasks[ctx.ComponentData.CustomId]
-				if ok {
-					callback := cb.(func(b BotUser, ctx Context, values ...string))
-					go callback(*sock.self, *ctx, ctx.ComponentData.Values...)
-				}
+		case func(bot BotUser, ctx Context, user User, member Member):
+			if hook != nil {
+				user, member := ctx.TargetUser()
+				go hook(*sock.self, *ctx, user, member)
+				return true
 			}
-			tmp, ok := timeoutTasks[ctx.ComponentData.CustomId]
-			if ok {
-				onTimeoutHandler := tmp[1].(func(b BotUser, ctx Context))
-				duration := tmp[0].(float64)
-				delete(timeoutTasks, ctx.ComponentData.CustomId)
-				go scheduleTimeoutTask(duration, *sock.self, *ctx, onTimeoutHandler)
+		case func(bot BotUser, ctx Context, message Message):
+			if hook != nil {
+				go hook(*sock.self, *ctx, ctx.TargetMessage())
+				return true
 			}
-		case 4:
-			// handle auto-complete interaction
-		case 5:
-			callback, ok := callbackTasks[ctx.ComponentData.CustomId]
-			if ok {
-				go callback.(func(b BotUser, ctx *Context))(*sock.self, ctx)
-				delete(callbackTasks, ctx.ComponentData.CustomId)
+		}
+	case 3:
+		ctx.ComponentData = unmarshalComponentData(data)
+		h, view, ok := sock.callbacks().resolve(ctx.ComponentData.CustomId)
+		if !ok {
+			return sock.route(ctx) || hooked
+		}
+		if view != nil {
+			if !view.allows(*sock.self, *ctx) {
+				return true
 			}
-		default:
-			log.Println("Unknown interaction type: ", ctx.Type)
+			ctx.view = view
+			view.state.touch(*ctx)
+		}
+		if !sock.allow(guard{checks: h.checks, cooldown: h.cooldown}, ctx) {
+			return true
 		}
+		switch callback := h.fn.(type) {
+		case func(b BotUser, ctx Context):
+			go callback(*sock.self, *ctx)
+			return true
+		case func(b BotUser, ctx Context, values ...string):
+			go callback(*sock.self, *ctx, ctx.ComponentData.Values...)
+			return true
+		case func(b BotUser, ctx Context, selection Selection):
+			go callback(*sock.self, *ctx, ctx.ComponentData.Resolved.Selection(ctx.ComponentData.Values))
+			return true
+		}
+	case 4:
+		// handle auto-complete interaction
+	case 5:
+		ctx.ComponentData = unmarshalComponentData(data)
+		h, view, ok := sock.callbacks().resolve(ctx.ComponentData.CustomId)
+		if !ok {
+			return sock.route(ctx) || hooked
+		}
+		sock.callbacks().remove(ctx.ComponentData.CustomId)
+		if view != nil {
+			if !view.allows(*sock.self, *ctx) {
+				return true
+			}
+			ctx.view = view
+			view.state.touch(*ctx)
+		}
+		go h.fn.(func(b BotUser, ctx Context))(*sock.self, *ctx)
+		return true
 	default:
+		log.Println("Unknown interaction type: ", ctx.Type)
 	}
+	return hooked
 }
 
-func scheduleTimeoutTask(timeout float64, user BotUser, ctx Context,
-	handler func(bot BotUser, ctx Context)) {
-	time.Sleep(time.Duration(timeout) * time.Second)
-	handler(user, ctx)
+// callbacks returns the handlers of the connection, kept in the Callbacks store
+func (sock *Socket) callbacks() *Callbacks {
+	sock.mu.Lock()
+	defer sock.mu.Unlock()
+	if sock.handlers == nil {
+		sock.handlers = newCallbacks(sock.Callbacks)
+	}
+	return sock.handlers
 }
 
-func requestMembers(conn *websocket.Conn, guildId string) {
-	_ = conn.WriteJSON(map[string]interface{}{
-		"op": 8,
-		"d": map[string]interface{}{
-			"guild_id": guildId,
-			"query":    "",
-			"limit":    0,
-		},
-	})
+func unmarshalComponentData(data map[string]interface{}) ComponentData {
+	var compdata ComponentData
+	da, _ := json.Marshal(data["data"])
+	_ = json.Unmarshal(da, &compdata)
+	compdata.Resolved.link()
+	return compdata
+}
+
+// route invokes the first persistent component handler matching the custom id,
+// reporting whether one matched
+func (sock *Socket) route(ctx *Context) bool {
+	for _, r := range sock.routes {
+		if params, ok := r.match(ctx.ComponentData.CustomId); ok {
+			if sock.allow(r.guard, ctx) {
+				go r.handler(*sock.self, *ctx, params)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// allow runs the checks and cooldown of a guard,
+// reporting the failure to the user when one of them rejects
+func (sock *Socket) allow(g guard, ctx *Context) bool {
+	err := g.run(*sock.self, *ctx)
+	if err == nil {
+		return true
+	}
+	if sock.CheckFailure != nil {
+		go sock.CheckFailure(*sock.self, *ctx, err)
+	} else {
+		go defaultCheckFailure(*sock.self, *ctx, err)
+	}
+	return false
 }
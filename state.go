@@ -0,0 +1,615 @@
+package disgo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// State is a cache of guilds, channels, roles and members
+// kept in sync with gateway events, safe for concurrent use
+type State struct {
+	mu       sync.RWMutex
+	policy   CachePolicy
+	backend  CacheBackend
+	dirty    map[Snowflake]*Guild    // guilds loaded by the event being applied
+	guilds   map[Snowflake]bool      // ids of cached guilds
+	channels map[Snowflake]Snowflake // channel or thread id to guild id
+	members  *lru                    // guild id:user id of cached members
+	stale    map[string]bool         // guild id:user id of restored members not seen since
+	memoized bool                    // a full member request follows every GUILD_CREATE
+	messages *lru                    // message id to Message
+}
+
+func newState() *State {
+	s := &State{
+		backend:  NewMemoryCache(),
+		guilds:   map[Snowflake]bool{},
+		channels: map[Snowflake]Snowflake{},
+		stale:    map[string]bool{},
+	}
+	s.members = newLRU(0, 0, s.evictMember)
+	s.messages = newLRU(1000, 0, nil)
+	return s
+}
+
+// SetBackend replaces the storage of guilds, indexing the channels
+// and members the backend already holds, e.g. from a snapshot;
+// restored members are dropped once the fresh member list of their guild leaves them out,
+// the full member request after GUILD_CREATE when memoizing, the GUILD_CREATE payload otherwise
+func (s *State) SetBackend(backend CacheBackend) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backend = backend
+	s.guilds = map[Snowflake]bool{}
+	s.channels = map[Snowflake]Snowflake{}
+	s.stale = map[string]bool{}
+	s.members = newLRU(s.policy.MaxMembers, seconds(s.policy.MemberTTL), s.evictMember)
+	var ids []Snowflake
+	backend.Range(func(g *Guild) bool {
+		ids = append(ids, g.Id)
+		return true
+	})
+	for _, id := range ids {
+		s.guilds[id] = true
+		for _, ch := range backend.Channels(id) {
+			s.channels[ch.Id] = id
+		}
+		for _, m := range backend.Members(id) {
+			s.members.put(memberKey(id, m.User.Id), nil)
+			s.stale[memberKey(id, m.User.Id)] = true
+		}
+	}
+}
+
+// Close flushes the backend, e.g. writing a snapshot to disk
+func (s *State) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backend.Close()
+}
+
+// SetPolicy changes which entities are cached, entities already
+// cached are only dropped once the new bounds are exceeded
+func (s *State) SetPolicy(policy CachePolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policy = policy
+	members := newLRU(policy.MaxMembers, seconds(policy.MemberTTL), s.evictMember)
+	for el := s.members.order.Back(); el != nil; el = el.Prev() {
+		members.put(el.Value.(*lruEntry).key, nil)
+	}
+	s.members = members
+	if policy.MaxMessages <= 0 {
+		policy.MaxMessages = 1000
+	}
+	s.messages.max, s.messages.ttl = policy.MaxMessages, seconds(policy.MessageTTL)
+	s.messages.prune()
+}
+
+// Caches reports whether the policy caches every entity of the flags
+func (s *State) Caches(flags int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.policy.has(flags)
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func memberKey(guildId Snowflake, userId Snowflake) string {
+	return string(guildId + ":" + userId)
+}
+
+func (s *State) evictMember(key string, _ interface{}) {
+	delete(s.stale, key)
+	guildId, userId, _ := strings.Cut(key, ":")
+	s.backend.DeleteMember(Snowflake(guildId), Snowflake(userId))
+}
+
+// load returns a guild for the event being applied, it is stored back once the event is applied
+func (s *State) load(id Snowflake) (*Guild, bool) {
+	if g, ok := s.dirty[id]; ok {
+		return g, true
+	}
+	g, ok := s.backend.Load(id)
+	if ok {
+		s.dirty[id] = g
+	}
+	return g, ok
+}
+
+// snapshot assembles a guild with its entities so callers never share maps with the cache
+func (s *State) snapshot(guild *Guild) Guild {
+	c := *guild
+	c.Roles = map[Snowflake]Role{}
+	for _, r := range s.backend.Roles(guild.Id) {
+		c.Roles[r.Id] = r
+	}
+	c.Members = map[Snowflake]Member{}
+	for _, m := range s.backend.Members(guild.Id) {
+		c.Members[m.User.Id] = m
+	}
+	c.Channels = map[Snowflake]Channel{}
+	for _, ch := range s.backend.Channels(guild.Id) {
+		c.Channels[ch.Id] = ch
+	}
+	if guild.VoiceStates != nil {
+		c.VoiceStates = make(map[Snowflake]map[string]interface{}, len(guild.VoiceStates))
+		for k, v := range guild.VoiceStates {
+			c.VoiceStates[k] = v
+		}
+	}
+	if guild.Presences != nil {
+		c.Presences = make(map[Snowflake]map[string]interface{}, len(guild.Presences))
+		for k, v := range guild.Presences {
+			c.Presences[k] = v
+		}
+	}
+	return c
+}
+
+// Guild returns a snapshot of a cached guild
+func (s *State) Guild(id Snowflake) (Guild, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members.prune()
+	g, ok := s.backend.Load(id)
+	if !ok {
+		return Guild{}, false
+	}
+	return s.snapshot(g), true
+}
+
+// Guilds returns snapshots of every cached guild
+func (s *State) Guilds() []Guild {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members.prune()
+	var cached []*Guild
+	s.backend.Range(func(g *Guild) bool {
+		cached = append(cached, g)
+		return true
+	})
+	var guilds []Guild
+	for _, g := range cached {
+		guilds = append(guilds, s.snapshot(g))
+	}
+	return guilds
+}
+
+// Channel returns a cached guild channel or thread
+func (s *State) Channel(id Snowflake) (Channel, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	guildId, ok := s.channels[id]
+	if !ok {
+		return Channel{}, false
+	}
+	return s.backend.Channel(guildId, id)
+}
+
+// Role returns a cached role of a guild
+func (s *State) Role(guildId Snowflake, roleId Snowflake) (Role, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.backend.Role(guildId, roleId)
+}
+
+// Member returns a cached member of a guild
+func (s *State) Member(guildId Snowflake, userId Snowflake) (Member, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.members.get(memberKey(guildId, userId)); !ok {
+		return Member{}, false
+	}
+	return s.backend.Member(guildId, userId)
+}
+
+// Permissions computes the permissions of a cached member in a channel or thread,
+// or guild wide when channelId is empty
+func (s *State) Permissions(guildId Snowflake, userId Snowflake, channelId Snowflake) (Permissions, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.members.get(memberKey(guildId, userId)); !ok {
+		return 0, false
+	}
+	m, ok := s.backend.Member(guildId, userId)
+	if !ok {
+		return 0, false
+	}
+	g, ok := s.backend.Load(guildId)
+	if !ok {
+		return 0, false
+	}
+	// only the everyone role and the roles of the member are read
+	g.Roles = map[Snowflake]Role{}
+	for _, id := range append([]Snowflake{guildId}, m.Roles...) {
+		if r, ok := s.backend.Role(guildId, id); ok {
+			g.Roles[id] = r
+		}
+	}
+	if channelId == "" {
+		return g.BasePermissions(m), true
+	}
+	ch, ok := s.backend.Channel(guildId, channelId)
+	if !ok {
+		return 0, false
+	}
+	if ch.Type.IsThread() {
+		// threads inherit the overwrites of their parent
+		if ch, ok = s.backend.Channel(guildId, ch.ParentId); !ok {
+			return 0, false
+		}
+	}
+	return g.ChannelPermissions(m, ch), true
+}
+
+// Message returns a cached message
+func (s *State) Message(id Snowflake) (Message, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg, ok := s.messages.get(string(id))
+	if !ok {
+		return Message{}, false
+	}
+	return msg.(Message), true
+}
+
+// popMessage removes a message from the cache, returning it when it was cached
+func (s *State) popMessage(id Snowflake) *Message {
+	msg, ok := s.messages.get(string(id))
+	if !ok {
+		return nil
+	}
+	s.messages.remove(string(id))
+	m := msg.(Message)
+	return &m
+}
+
+// merge applies a partial payload to the value v points to, keeping the fields it does not carry;
+// the result is decoded into a fresh value so slices handed out in snapshots are never written to
+func merge(v interface{}, payload interface{}) {
+	fresh := reflect.New(reflect.TypeOf(v).Elem())
+	current, _ := json.Marshal(v)
+	_ = json.Unmarshal(current, fresh.Interface())
+	data, _ := json.Marshal(payload)
+	_ = json.Unmarshal(data, fresh.Interface())
+	reflect.ValueOf(v).Elem().Set(fresh.Elem())
+}
+
+func (s *State) putChannel(guildId Snowflake, payload interface{}) {
+	if !s.policy.has(CacheChannels) {
+		return
+	}
+	ch := UnmarshalChannel(payload)
+	if ch.GuildId == "" {
+		ch.GuildId = guildId
+	}
+	s.backend.StoreChannel(*ch)
+	s.channels[ch.Id] = guildId
+}
+
+func (s *State) putRole(guildId Snowflake, payload interface{}) {
+	r := DataToRole(payload)
+	r.GuildId = guildId
+	s.backend.StoreRole(*r)
+}
+
+func (s *State) putMember(event string, guildId Snowflake, payload interface{}) {
+	if !s.policy.has(CacheMembers) {
+		return
+	}
+	m := UnmarshalMember(payload)
+	if m.User.Id == "" {
+		return
+	}
+	m.GuildId = guildId
+	key := memberKey(guildId, m.User.Id)
+	if !s.members.has(key) {
+		if s.policy.MemberFilter != nil && !s.policy.MemberFilter(event, *m) {
+			return
+		}
+	}
+	s.backend.StoreMember(*m)
+	s.members.put(key, nil)
+	delete(s.stale, key)
+}
+
+func (s *State) dropMember(guildId Snowflake, userId Snowflake) {
+	key := memberKey(guildId, userId)
+	s.backend.DeleteMember(guildId, userId)
+	s.members.remove(key)
+	delete(s.stale, key)
+}
+
+// pruneStale drops the restored members of a guild left out of a full member list
+func (s *State) pruneStale(guildId Snowflake) {
+	for key := range s.stale {
+		if id, userId, _ := strings.Cut(key, ":"); Snowflake(id) == guildId {
+			s.dropMember(guildId, Snowflake(userId))
+		}
+	}
+}
+
+func (s *State) dropGuild(id Snowflake) {
+	for _, ch := range s.backend.Channels(id) {
+		delete(s.channels, ch.Id)
+	}
+	for _, m := range s.backend.Members(id) {
+		s.members.remove(memberKey(id, m.User.Id))
+		delete(s.stale, memberKey(id, m.User.Id))
+	}
+	delete(s.guilds, id)
+	delete(s.dirty, id)
+	s.backend.Delete(id)
+}
+
+// upsert sets the entry of a user in a map of the guild, removing it when keep is false
+func upsert(entries map[Snowflake]map[string]interface{}, userId Snowflake, entry map[string]interface{}, keep bool) map[Snowflake]map[string]interface{} {
+	if !keep {
+		delete(entries, userId)
+		return entries
+	}
+	if entries == nil {
+		entries = map[Snowflake]map[string]interface{}{}
+	}
+	entries[userId] = entry
+	return entries
+}
+
+// apply updates the cache from a gateway event, returning the previously
+// cached message of MESSAGE_UPDATE and MESSAGE_DELETE or messages of MESSAGE_DELETE_BULK
+func (s *State) apply(event string, data map[string]interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = map[Snowflake]*Guild{}
+	defer func() {
+		for _, g := range s.dirty {
+			s.backend.Store(g)
+		}
+		s.dirty = nil
+	}()
+	guildId := snowflakeOf(data["guild_id"])
+	switch event {
+	case "READY":
+		ids := map[Snowflake]bool{}
+		guilds, _ := data["guilds"].([]interface{})
+		for _, o := range guilds {
+			if g, ok := o.(map[string]interface{}); ok {
+				ids[snowflakeOf(g["id"])] = true
+			}
+		}
+		var stale []Snowflake
+		s.backend.Range(func(g *Guild) bool {
+			if !ids[g.Id] {
+				stale = append(stale, g.Id)
+			}
+			return true
+		})
+		for _, id := range stale {
+			s.dropGuild(id)
+		}
+	case "MESSAGE_UPDATE":
+		id := snowflakeOf(data["id"])
+		before := s.popMessage(id)
+		if before != nil {
+			after := UnmarshalMessage(before)
+			merge(after, data)
+			s.messages.put(string(id), *after)
+			return before
+		}
+		if s.policy.has(CacheMessages) && data["author"] != nil {
+			s.messages.put(string(id), *UnmarshalMessage(data))
+		}
+		return before
+	case "MESSAGE_DELETE":
+		id := snowflakeOf(data["id"])
+		return s.popMessage(id)
+	case "MESSAGE_DELETE_BULK":
+		ids, _ := data["ids"].([]interface{})
+		var cached []Message
+		for _, id := range ids {
+			if m := s.popMessage(snowflakeOf(id)); m != nil {
+				cached = append(cached, *m)
+			}
+		}
+		return cached
+	case "GUILD_CREATE":
+		if !s.policy.has(CacheGuilds) {
+			return nil
+		}
+		g := UnmarshalGuild(data)
+		g.Roles, g.Members, g.Channels = nil, nil, nil
+		if s.guilds[g.Id] {
+			// the fresh payload is authoritative for channels and roles,
+			// members cached earlier or restored from a snapshot are kept
+			for _, ch := range s.backend.Channels(g.Id) {
+				s.backend.DeleteChannel(g.Id, ch.Id)
+				delete(s.channels, ch.Id)
+			}
+			for _, r := range s.backend.Roles(g.Id) {
+				s.backend.DeleteRole(g.Id, r.Id)
+			}
+			if !s.policy.has(CacheMembers) {
+				for _, m := range s.backend.Members(g.Id) {
+					s.dropMember(g.Id, m.User.Id)
+				}
+			}
+		}
+		if !s.policy.has(CachePresences) {
+			g.Presences = nil
+		}
+		if !s.policy.has(CacheVoiceStates) {
+			g.VoiceStates = nil
+		}
+		if !s.policy.has(CacheEmojis) {
+			g.Emojis = nil
+		}
+		if !s.policy.has(CacheStickers) {
+			g.Stickers = nil
+		}
+		s.backend.Store(g)
+		s.guilds[g.Id] = true
+		delete(s.dirty, g.Id)
+		if roles, ok := data["roles"].([]interface{}); ok && s.policy.has(CacheRoles) {
+			for _, o := range roles {
+				s.putRole(g.Id, o)
+			}
+		}
+		for _, key := range []string{"channels", "threads"} {
+			objs, _ := data[key].([]interface{})
+			for _, o := range objs {
+				s.putChannel(g.Id, o)
+			}
+		}
+		members, _ := data["members"].([]interface{})
+		for _, o := range members {
+			s.putMember(event, g.Id, o)
+		}
+		if !s.memoized || !s.policy.has(CacheMembers) {
+			// no full member list follows to reconcile the restored members against
+			s.pruneStale(g.Id)
+		}
+	case "GUILD_UPDATE":
+		id := snowflakeOf(data["id"])
+		if g, ok := s.load(id); ok {
+			// presences and voice states are kept as they are, they are not part of the payload
+			voiceStates, presences := g.VoiceStates, g.Presences
+			g.VoiceStates, g.Presences = nil, nil
+			merge(g, data)
+			g.VoiceStates, g.Presences = voiceStates, presences
+			if roles, ok := data["roles"].([]interface{}); ok && s.policy.has(CacheRoles) {
+				for _, r := range s.backend.Roles(id) {
+					s.backend.DeleteRole(id, r.Id)
+				}
+				for _, o := range roles {
+					s.putRole(id, o)
+				}
+			}
+		}
+	case "GUILD_DELETE":
+		id := snowflakeOf(data["id"])
+		g, ok := s.load(id)
+		if !ok {
+			return nil
+		}
+		if unavailable, _ := data["unavailable"].(bool); unavailable {
+			g.Unavailable = true
+			return nil
+		}
+		s.dropGuild(id)
+	case "CHANNEL_CREATE", "CHANNEL_UPDATE", "THREAD_CREATE", "THREAD_UPDATE":
+		if s.guilds[guildId] {
+			s.putChannel(guildId, data)
+		}
+	case "CHANNEL_DELETE", "THREAD_DELETE":
+		id := snowflakeOf(data["id"])
+		if s.guilds[guildId] {
+			s.backend.DeleteChannel(guildId, id)
+		}
+		delete(s.channels, id)
+	case "THREAD_LIST_SYNC":
+		if s.guilds[guildId] {
+			threads, _ := data["threads"].([]interface{})
+			for _, o := range threads {
+				s.putChannel(guildId, o)
+			}
+		}
+	case "GUILD_ROLE_CREATE", "GUILD_ROLE_UPDATE":
+		if s.guilds[guildId] && s.policy.has(CacheRoles) {
+			s.putRole(guildId, data["role"])
+		}
+	case "GUILD_ROLE_DELETE":
+		if s.guilds[guildId] {
+			s.backend.DeleteRole(guildId, snowflakeOf(data["role_id"]))
+		}
+	case "GUILD_MEMBER_ADD":
+		if g, ok := s.load(guildId); ok {
+			s.putMember(event, guildId, data)
+			g.MemberCount++
+		}
+	case "GUILD_MEMBER_UPDATE":
+		if s.guilds[guildId] {
+			user, _ := data["user"].(map[string]interface{})
+			id := snowflakeOf(user["id"])
+			if m, ok := s.backend.Member(guildId, id); ok {
+				merge(&m, data)
+				s.backend.StoreMember(m)
+				s.members.put(memberKey(guildId, id), nil)
+				delete(s.stale, memberKey(guildId, id))
+			} else {
+				s.putMember(event, guildId, data)
+			}
+		}
+	case "GUILD_MEMBER_REMOVE":
+		if g, ok := s.load(guildId); ok {
+			user, _ := data["user"].(map[string]interface{})
+			s.dropMember(guildId, snowflakeOf(user["id"]))
+			g.MemberCount--
+		}
+	case "GUILD_MEMBERS_CHUNK":
+		if s.guilds[guildId] {
+			members, _ := data["members"].([]interface{})
+			for _, o := range members {
+				s.putMember(event, guildId, o)
+			}
+			// chunks without a nonce answer the full member request sent on GUILD_CREATE
+			index, _ := data["chunk_index"].(float64)
+			count, _ := data["chunk_count"].(float64)
+			if nonce, _ := data["nonce"].(string); nonce == "" && index >= count-1 {
+				s.pruneStale(guildId)
+			}
+			presences, _ := data["presences"].([]interface{})
+			if len(presences) > 0 && s.policy.has(CachePresences) {
+				if g, ok := s.load(guildId); ok {
+					for _, o := range presences {
+						g.putPresence(o)
+					}
+				}
+			}
+		}
+	case "VOICE_STATE_UPDATE":
+		g, ok := s.load(guildId)
+		if !ok {
+			return nil
+		}
+		if member, ok := data["member"]; ok {
+			s.putMember(event, guildId, member)
+		}
+		if s.policy.has(CacheVoiceStates) {
+			g.putVoiceState(data)
+		}
+	case "PRESENCE_UPDATE":
+		if g, ok := s.load(guildId); ok && s.policy.has(CachePresences) {
+			g.putPresence(data)
+		}
+	case "GUILD_EMOJIS_UPDATE":
+		if g, ok := s.load(guildId); ok && s.policy.has(CacheEmojis) {
+			g.Emojis = nil
+			merge(&g.Emojis, data["emojis"])
+		}
+	case "GUILD_STICKERS_UPDATE":
+		if g, ok := s.load(guildId); ok && s.policy.has(CacheStickers) {
+			g.Stickers = nil
+			merge(&g.Stickers, data["stickers"])
+		}
+	case "MESSAGE_CREATE":
+		if s.policy.has(CacheMessages) {
+			msg := UnmarshalMessage(data)
+			s.messages.put(string(msg.Id), *msg)
+		}
+		member, ok := data["member"].(map[string]interface{})
+		if s.guilds[guildId] && ok {
+			m := map[string]interface{}{"user": data["author"]}
+			for k, v := range member {
+				m[k] = v
+			}
+			s.putMember(event, guildId, m)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package disgo
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Callback is what a CallbackStore keeps under the custom id of a component or modal;
+// it only names the handler and the view, which stay in the process that registered
+// them, so the store itself can live outside of the process
+type Callback struct {
+	Handler string `json:"handler"`        // key of the handler in the Callbacks of the connection
+	View    string `json:"view,omitempty"` // id of the view the component belongs to
+}
+
+// CallbackStore keeps the callbacks of a connection,
+// implementations must be safe for concurrent use
+type CallbackStore interface {
+	Set(id string, cb Callback)
+	Get(id string) (Callback, bool)
+	Delete(ids ...string)
+}
+
+// MemoryStore is the default in-process CallbackStore
+type MemoryStore struct {
+	mu        sync.RWMutex
+	callbacks map[string]Callback
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{callbacks: map[string]Callback{}}
+}
+
+func (s *MemoryStore) Set(id string, cb Callback) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callbacks[id] = cb
+}
+
+func (s *MemoryStore) Get(id string) (Callback, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cb, ok := s.callbacks[id]
+	return cb, ok
+}
+
+func (s *MemoryStore) Delete(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.callbacks, id)
+	}
+}
+
+// handler is a component or modal handler along with its checks
+type handler struct {
+	fn       interface{}
+	checks   []Check
+	cooldown *Cooldown
+}
+
+// Callbacks registers the component and modal handlers of a connection, the store maps
+// custom ids to a Callback and the handlers and views it names are resolved here.
+// Buttons, select menus, views, modals and responses are marshalled with the Callbacks
+// of the connection, marshalling a component which has a handler with nil panics
+type Callbacks struct {
+	store    CallbackStore
+	mu       sync.RWMutex
+	handlers map[string]handler
+	views    map[string]*View
+}
+
+func newCallbacks(store CallbackStore) *Callbacks {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Callbacks{store: store, handlers: map[string]handler{}, views: map[string]*View{}}
+}
+
+// register stores the handler under the custom id, as a component of the view when it is not nil
+func (c *Callbacks) register(customId string, h handler, view *View) {
+	if c == nil {
+		panic(fmt.Sprintf("Component (%s) has a handler, it must be marshalled with the Callbacks of the connection", customId))
+	}
+	old, replaced := c.store.Get(customId)
+	cb := Callback{Handler: AssignId("")}
+	c.mu.Lock()
+	if replaced {
+		delete(c.handlers, old.Handler)
+	}
+	c.handlers[cb.Handler] = h
+	if view != nil {
+		cb.View = view.state.id
+		c.views[cb.View] = view
+	}
+	c.mu.Unlock()
+	c.store.Set(customId, cb)
+}
+
+// resolve looks up the handler of a custom id and the view it belongs to,
+// callbacks registered by another process are not resolved
+func (c *Callbacks) resolve(customId string) (handler, *View, bool) {
+	cb, ok := c.store.Get(customId)
+	if !ok {
+		return handler{}, nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	h, ok := c.handlers[cb.Handler]
+	return h, c.views[cb.View], ok
+}
+
+// remove deletes the callbacks of the custom ids along with their handlers
+func (c *Callbacks) remove(ids ...string) {
+	if c == nil {
+		return
+	}
+	var keys []string
+	for _, id := range ids {
+		if cb, ok := c.store.Get(id); ok {
+			keys = append(keys, cb.Handler)
+		}
+	}
+	c.store.Delete(ids...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.handlers, key)
+	}
+}
+
+// dropView forgets a view once its callbacks have been removed
+func (c *Callbacks) dropView(id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.views, id)
+}
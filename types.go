@@ -6,11 +6,14 @@ const (
 	OnGuildDelete       = "GUILD_DELETE"
 	OnInteractionCreate = "INTERACTION_CREATE"
 	OnMessageCreate     = "MESSAGE_CREATE"
+	OnMessageUpdate     = "MESSAGE_UPDATE"
+	OnMessageDelete     = "MESSAGE_DELETE"
+	OnMessageDeleteBulk = "MESSAGE_DELETE_BULK"
 	OnSocketReceive     = "SOCKET_RECEIVE"
+	OnAppCmdPermsUpdate = "APPLICATION_COMMAND_PERMISSIONS_UPDATE"
 	// OnResumed                      = "RESUMED"
 	// onReconnect                 	  = "RECONNECT" --> handle internally
 	// onInvalidSession 		      = "INVALID_SESSION" --> handle internally
-	// onAppCmdPermsUpdate            = "APPLICATION_COMMAND_PERMISSIONS_UPDATE"
 	// onAutoModRuleCreate            = "AUTO_MODERATION_RULE_CREATE"
 	// onAutoModRuleDelete            = "AUTO_MODERATION_RULE_DELETE"
 	// onAutoModRuleUpdate            = "AUTO_MODERATION_RULE_UPDATE"
@@ -51,9 +54,6 @@ const (
 	// onInviteCreate                 = "INVITE_CREATE"
 	// onInviteDelete                 = "INVITE_DELETE"
 	// OnMessageCreate 				  = "MESSAGE_CREATE"
-	// onMessageUpdate                = "MESSAGE_UPDATE"
-	// onMessageDelete                = "MESSAGE_DELETE"
-	// onMessageDeleteBulk            = "MESSAGE_DELETE_BULK"
 	// onMessageReactionAdd           = "MESSAGE_REACTION_ADD"
 	// onMessageReactionRemove        = "MESSAGE_REACTION_REMOVE"
 	// onMessageReactionRemoveAll     = "MESSAGE_REACTION_REMOVE_ALL"
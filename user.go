@@ -3,21 +3,21 @@ package disgo
 import "encoding/json"
 
 type User struct {
-	Id            string `json:"id"`
-	Username      string `json:"username"`
-	Discriminator string `json:"discriminator"`
-	Avatar        string `json:"avatar"`
-	Bot           bool   `json:"bot"`
-	System        bool   `json:"system"`
-	MfaEnabled    bool   `json:"mfa_enabled"`
-	Banner        string `json:"banner"`
-	Color         int    `json:"accent_color"`
-	Locale        string `json:"locale"`
-	Verified      bool   `json:"verified"`
-	Email         string `json:"email"`
-	Flags         int    `json:"flags"`
-	PremiumType   int    `json:"premium_type"`
-	PublicFlags   int    `json:"public_flags"`
+	Id            Snowflake `json:"id"`
+	Username      string    `json:"username"`
+	Discriminator string    `json:"discriminator"`
+	Avatar        string    `json:"avatar"`
+	Bot           bool      `json:"bot"`
+	System        bool      `json:"system"`
+	MfaEnabled    bool      `json:"mfa_enabled"`
+	Banner        string    `json:"banner"`
+	Color         int       `json:"accent_color"`
+	Locale        string    `json:"locale"`
+	Verified      bool      `json:"verified"`
+	Email         string    `json:"email"`
+	Flags         int       `json:"flags"`
+	PremiumType   int       `json:"premium_type"`
+	PublicFlags   int       `json:"public_flags"`
 }
 
 func DataToUser(payload interface{}) *User {
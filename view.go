@@ -1,47 +1,67 @@
 package disgo
 
 import (
+	"fmt"
 	"log"
+	"net/url"
 )
 
-var callbackTasks = map[string]interface{}{}
-var timeoutTasks = map[string][]interface{}{}
-
 type Button struct {
-	Style    int    // default: 1 (blue) More: 2 (grey), 3 (green), 4 (red), 5 (link)
-	Label    string // default: "Button"
+	Style    int    // default: 1 (blue) More: 2 (grey), 3 (green), 4 (red), 5 (link), 6 (premium)
+	Label    string // default: "Button" when no emoji is set
 	Emoji    PartialEmoji
-	URL      string // only for style 5 (link)
+	URL      string    // only for style 5 (link)
+	SkuId    Snowflake // only for style 6 (premium)
 	Disabled bool
-	CustomId string // filled internally
+	CustomId string // default: random, set a stable id to use a persistent component handler
 	OnClick  func(bot BotUser, ctx Context)
+	Checks   []Check
+	Cooldown *Cooldown
 }
 
-func (b *Button) Marshal() map[string]interface{} {
-	b.CustomId = AssignId("")
-	if b.OnClick != nil {
-		callbackTasks[b.CustomId] = b.OnClick
-	}
-	btn := map[string]interface{}{
-		"type":      2,
-		"custom_id": b.CustomId,
-	}
-	if b.Style != 0 {
-		btn["style"] = b.Style
-	} else {
-		btn["style"] = 1
+// Marshal builds the button payload, registering OnClick in callbacks
+func (b *Button) Marshal(callbacks *Callbacks) map[string]interface{} {
+	return b.marshal(callbacks, nil)
+}
+
+func (b *Button) marshal(callbacks *Callbacks, view *View) map[string]interface{} {
+	btn := map[string]interface{}{"type": 2}
+	switch b.Style {
+	case 5:
+		u, err := url.Parse(b.URL)
+		if err != nil || u.Host == "" || u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "discord" {
+			panic(fmt.Sprintf("Link button {url} must be a valid http, https or discord url, got (%s)", b.URL))
+		}
+		btn["url"] = b.URL
+	case 6:
+		if b.SkuId == "" {
+			panic("Premium button {sku id} must be set")
+		}
+		btn["style"] = 6
+		btn["sku_id"] = b.SkuId
+		if b.Disabled {
+			btn["disabled"] = true
+		}
+		return btn
+	default:
+		if b.Style < 1 || b.Style > 4 {
+			b.Style = 1
+		}
+		b.CustomId = AssignId(b.CustomId)
+		btn["custom_id"] = b.CustomId
+		if b.OnClick != nil {
+			callbacks.register(b.CustomId, handler{fn: b.OnClick, checks: b.Checks, cooldown: b.Cooldown}, view)
+		}
 	}
+	btn["style"] = b.Style
 	if b.Label != "" {
 		btn["label"] = b.Label
-	} else {
+	} else if b.Emoji.Id == "" && b.Emoji.Name == "" {
 		btn["label"] = "Button"
 	}
-	if b.Emoji.Id != "" {
+	if b.Emoji.Id != "" || b.Emoji.Name != "" {
 		btn["emoji"] = b.Emoji
 	}
-	if b.URL != "" && b.Style == 5 {
-		btn["url"] = b.URL
-	}
 	if b.Disabled {
 		btn["disabled"] = true
 	}
@@ -69,7 +89,7 @@ func (so *SelectOption) ToComponent() map[string]interface{} {
 	} else {
 		panic("Description of the option can contain max 100 characters")
 	}
-	if so.Emoji.Id != "" {
+	if so.Emoji.Id != "" || so.Emoji.Name != "" {
 		op["emoji"] = so.Emoji
 	}
 	if so.Default {
@@ -78,22 +98,63 @@ func (so *SelectOption) ToComponent() map[string]interface{} {
 	return op
 }
 
+// DefaultValue is a pre-selected user, role or channel of an auto-populated select menu
+type DefaultValue struct {
+	Id   Snowflake `json:"id"`
+	Type string    `json:"type"` // "user", "role" or "channel"
+}
+
+// Selection contains the values chosen in a select menu
+// along with the resolved objects of auto-populated menus
+type Selection struct {
+	Values   []string
+	Users    []User
+	Members  []Member
+	Roles    []Role
+	Channels []Channel
+}
+
 type SelectMenu struct {
-	CustomId    string         // filled internally
-	Options     []SelectOption // max 25 options
-	Placeholder string         // max 100 characters
-	MinValues   int            // default: 0
-	MaxValues   int            // default: 1
-	Disabled    bool
-	OnSelection func(bot BotUser, ctx Context, values ...string)
+	Type                int            // default: 3 (string) More: 5 (user), 6 (role), 7 (mentionable), 8 (channel)
+	CustomId            string         // default: random, set a stable id to use a persistent component handler
+	Options             []SelectOption // max 25 options, for type 3 only
+	ChannelTypes        []ChannelType  // for type 8 only
+	DefaultValues       []DefaultValue // for type 5, 6, 7 and 8 only
+	Placeholder         string         // max 100 characters
+	MinValues           int            // default: 0
+	MaxValues           int            // default: 1
+	Disabled            bool
+	OnSelection         func(bot BotUser, ctx Context, values ...string)
+	OnResolvedSelection func(bot BotUser, ctx Context, selection Selection)
+	Checks              []Check
+	Cooldown            *Cooldown
+}
+
+func (s *SelectMenu) isSet() bool {
+	return len(s.Options) > 0 || s.Type >= 5 && s.Type <= 8
 }
 
-func (s *SelectMenu) ToComponent() map[string]interface{} {
-	s.CustomId = AssignId("")
-	if s.OnSelection != nil {
-		callbackTasks[s.CustomId] = s.OnSelection
+// ToComponent builds the select menu payload, registering its selection handler in callbacks
+func (s *SelectMenu) ToComponent(callbacks *Callbacks) map[string]interface{} {
+	return s.marshal(callbacks, nil)
+}
+
+func (s *SelectMenu) marshal(callbacks *Callbacks, view *View) map[string]interface{} {
+	s.CustomId = AssignId(s.CustomId)
+	h := handler{checks: s.Checks, cooldown: s.Cooldown}
+	if s.OnResolvedSelection != nil {
+		h.fn = s.OnResolvedSelection
+		callbacks.register(s.CustomId, h, view)
+	} else if s.OnSelection != nil {
+		h.fn = s.OnSelection
+		callbacks.register(s.CustomId, h, view)
+	}
+	switch s.Type {
+	case 5, 6, 7, 8:
+	default:
+		s.Type = 3
 	}
-	menu := map[string]interface{}{"type": 3, "custom_id": s.CustomId}
+	menu := map[string]interface{}{"type": s.Type, "custom_id": s.CustomId}
 	if s.Placeholder != "" {
 		menu["placeholder"] = s.Placeholder
 	}
@@ -114,6 +175,15 @@ func (s *SelectMenu) ToComponent() map[string]interface{} {
 	if s.Disabled {
 		menu["disabled"] = true
 	}
+	if s.Type != 3 {
+		if len(s.ChannelTypes) > 0 && s.Type == 8 {
+			menu["channel_types"] = s.ChannelTypes
+		}
+		if len(s.DefaultValues) > 0 {
+			menu["default_values"] = s.DefaultValues
+		}
+		return menu
+	}
 	if len(s.Options) > 25 {
 		s.Options = s.Options[:25]
 	}
@@ -129,10 +199,75 @@ type ActionRow struct {
 	SelectMenu SelectMenu
 }
 
+// marshal builds the action row, recording the custom ids of its components in ids
+func (row *ActionRow) marshal(callbacks *Callbacks, view *View, ids map[string]bool) map[string]interface{} {
+	num := 0
+	tmp := map[string]interface{}{
+		"type":       1,
+		"components": []interface{}{},
+	}
+	for _, button := range row.Buttons {
+		if num < 5 {
+			tmp["components"] = append(tmp["components"].([]interface{}), button.marshal(callbacks, view))
+			if button.CustomId != "" {
+				ids[button.CustomId] = true
+			}
+			num++
+		}
+	}
+	if row.SelectMenu.isSet() {
+		if num == 0 {
+			tmp["components"] = append(tmp["components"].([]interface{}), row.SelectMenu.marshal(callbacks, view))
+			ids[row.SelectMenu.CustomId] = true
+		} else {
+			log.Println("Single ActionRow can contain either 1x SelectMenu or max 5x Buttons")
+		}
+	}
+	if len(tmp["components"].([]interface{})) == 0 {
+		return nil
+	}
+	return tmp
+}
+
 type View struct {
-	Timeout    float64     // default: 15 * 60 seconds
-	ActionRows []ActionRow // max 5 rows
-	OnTimeout  func(bot BotUser, ctx Context)
+	Timeout           float64     // seconds of inactivity, default: 15 * 60 seconds
+	ActionRows        []ActionRow // max 5 rows
+	OnTimeout         func(bot BotUser, ctx Context)
+	DisableComponents bool        // disable all components of the message when the view times out or stops
+	AllowedUsers      []Snowflake // ids of users allowed to interact, default: everyone
+	InteractionCheck  func(bot BotUser, ctx Context) bool
+	OnReject          func(bot BotUser, ctx Context) // default: ephemeral "This isn't for you"
+	state             *viewState
+}
+
+// NewView creates a view which can be stopped through the returned value after
+// it has been sent; it is required for Stop, a View literal is copied into the
+// Response and the copy is the one tracked
+func NewView(timeout float64) *View {
+	return &View{Timeout: timeout, state: newViewState()}
+}
+
+// allows reports whether the user of the interaction may use the view,
+// responding with the rejection handler otherwise
+func (v *View) allows(bot BotUser, ctx Context) bool {
+	allowed := len(v.AllowedUsers) == 0
+	for _, id := range v.AllowedUsers {
+		if id == ctx.Author().Id {
+			allowed = true
+			break
+		}
+	}
+	if allowed && v.InteractionCheck != nil {
+		allowed = v.InteractionCheck(bot, ctx)
+	}
+	if !allowed {
+		if v.OnReject != nil {
+			go v.OnReject(bot, ctx)
+		} else {
+			go ctx.Send(Response{Content: "This isn't for you", Ephemeral: true})
+		}
+	}
+	return allowed
 }
 
 func (v *View) AddRow(row ActionRow) {
@@ -155,10 +290,17 @@ func (v *View) AddSelectMenu(menu SelectMenu) {
 	}
 }
 
-func (v *View) ToComponent() []interface{} {
-	const timeout = 14.98 * 60
-	if v.Timeout == 0 || v.Timeout > timeout {
-		v.Timeout = timeout
+// ToComponent builds the action rows of the view, registering the handlers of its components in callbacks
+func (v *View) ToComponent(callbacks *Callbacks) []interface{} {
+	return v.marshal(callbacks)
+}
+
+func (v *View) marshal(callbacks *Callbacks) []interface{} {
+	if v.Timeout <= 0 {
+		v.Timeout = 15 * 60
+	}
+	if v.state == nil {
+		v.state = newViewState()
 	}
 	var undo = map[string]bool{}
 	var c []interface{}
@@ -166,36 +308,12 @@ func (v *View) ToComponent() []interface{} {
 		v.ActionRows = v.ActionRows[:5]
 	}
 	for _, row := range v.ActionRows {
-		num := 0
-		tmp := map[string]interface{}{
-			"type":       1,
-			"components": []interface{}{},
-		}
-		for _, button := range row.Buttons {
-			if num < 5 {
-				undo[button.CustomId] = true
-				if v.OnTimeout != nil {
-					timeoutTasks[button.CustomId] = []interface{}{v.Timeout, v.OnTimeout}
-				}
-				tmp["components"] = append(tmp["components"].([]interface{}), button.Marshal())
-				num++
-			}
-		}
-		if len(row.SelectMenu.Options) > 0 {
-			if num == 0 {
-				undo[row.SelectMenu.CustomId] = true
-				if v.OnTimeout != nil {
-					timeoutTasks[row.SelectMenu.CustomId] = []interface{}{v.Timeout, v.OnTimeout}
-				}
-				tmp["components"] = append(tmp["components"].([]interface{}), row.SelectMenu.ToComponent())
-			} else {
-				log.Println("Single ActionRow can contain either 1x SelectMenu or max 5x Buttons")
-			}
-		}
-		if len(undo) > 0 {
+		if tmp := row.marshal(callbacks, v, undo); tmp != nil {
 			c = append(c, tmp)
-			go ScheduleDeletion(v.Timeout, callbackTasks, undo)
 		}
 	}
+	if len(undo) > 0 {
+		v.state.start(v, callbacks, undo, c)
+	}
 	return c
 }
@@ -0,0 +1,217 @@
+package disgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// viewState tracks a sent view, the message it is attached to and its inactivity timer
+type viewState struct {
+	mu         sync.Mutex
+	id         string
+	view       *View
+	callbacks  *Callbacks
+	ids        map[string]bool
+	components []interface{}
+	timer      *time.Timer
+	stopped    bool
+	ctx        Context
+	channelId  Snowflake
+	messageId  Snowflake
+	original   bool // the message is the original response of ctx
+	ephemeral  bool
+}
+
+func newViewState() *viewState {
+	return &viewState{id: AssignId("")}
+}
+
+// tokenLifetime is how long an interaction token can edit its messages
+const tokenLifetime = 15 * time.Minute
+
+// start (re)arms the view after its components have been marshalled,
+// a stopped view stays stopped and drops the new callbacks
+func (vs *viewState) start(v *View, callbacks *Callbacks, ids map[string]bool, components []interface{}) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.stopped {
+		for id := range ids {
+			callbacks.remove(id)
+		}
+		return
+	}
+	for id := range vs.ids {
+		if !ids[id] {
+			vs.callbacks.remove(id)
+		}
+	}
+	vs.view, vs.callbacks, vs.ids, vs.components = v, callbacks, ids, components
+	timeout := time.Duration(v.Timeout * float64(time.Second))
+	if vs.timer == nil {
+		vs.timer = time.AfterFunc(timeout, func() { vs.finish(true) })
+	} else {
+		vs.timer.Reset(timeout)
+	}
+}
+
+// own registers a callback which belongs to the view without being one
+// of its components, e.g. a modal, so it is removed along with the view
+func (vs *viewState) own(id string, h handler) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.stopped || vs.callbacks == nil {
+		return
+	}
+	vs.ids[id] = true
+	vs.callbacks.register(id, h, vs.view)
+}
+
+func (vs *viewState) isStopped() bool {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	return vs.stopped
+}
+
+// track records the interaction and message the view has been sent with,
+// an empty message id refers to the original response of the interaction
+func (vs *viewState) track(ctx Context, channelId Snowflake, messageId Snowflake, ephemeral bool) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	// an edit of the original response keeps the visibility it was sent with
+	vs.ephemeral = ephemeral || messageId == "" && vs.ctx.Token == ctx.Token && vs.ephemeral
+	vs.ctx = ctx
+	vs.channelId, vs.messageId = channelId, messageId
+	vs.original = messageId == "" || messageId == snowflakeOf(ctx.Message["id"])
+	if messageId == "" {
+		go vs.fetchOriginal(ctx)
+	}
+}
+
+// fetchOriginal resolves the id of the original response, so it can
+// still be edited after the interaction token has expired
+func (vs *viewState) fetchOriginal(ctx Context) {
+	time.Sleep(3 * time.Second)
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", ctx.ApplicationId, ctx.Token)
+	resp := MinimalReq("GET", path, nil, "").Request()
+	if resp == nil {
+		return
+	}
+	defer resp.Body.Close()
+	var msg struct {
+		Id        Snowflake `json:"id"`
+		ChannelId Snowflake `json:"channel_id"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(body, &msg) != nil || msg.Id == "" {
+		return
+	}
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.ctx.Token == ctx.Token && vs.messageId == "" {
+		vs.channelId, vs.messageId = msg.ChannelId, msg.Id
+	}
+}
+
+// touch resets the inactivity timer on every interaction with the view
+func (vs *viewState) touch(ctx Context) {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+	if vs.stopped || vs.timer == nil {
+		return
+	}
+	if ctx.Message != nil {
+		if id := snowflakeOf(ctx.Message["id"]); id != "" {
+			// the message of a component is the original response of its interaction
+			vs.channelId, vs.messageId, vs.original = ctx.ChannelId, id, true
+			vs.ephemeral = isEphemeral(ctx.Message)
+		}
+	}
+	vs.ctx = ctx
+	vs.timer.Reset(time.Duration(vs.view.Timeout * float64(time.Second)))
+}
+
+// finish stops the view, removes its callbacks and optionally
+// disables the components of the message it is attached to
+func (vs *viewState) finish(timedOut bool) {
+	vs.mu.Lock()
+	if vs.stopped || vs.view == nil {
+		vs.mu.Unlock()
+		return
+	}
+	vs.stopped = true
+	vs.timer.Stop()
+	for id := range vs.ids {
+		vs.callbacks.remove(id)
+	}
+	vs.callbacks.dropView(vs.id)
+	view, ctx := vs.view, vs.ctx
+	channelId, messageId, original, ephemeral := vs.channelId, vs.messageId, vs.original, vs.ephemeral
+	components := disableComponents(vs.components)
+	vs.mu.Unlock()
+	if timedOut && view.OnTimeout != nil && ctx.bot != nil {
+		view.OnTimeout(*ctx.bot, ctx)
+	}
+	if !view.DisableComponents || ctx.Token == "" {
+		return
+	}
+	body := map[string]interface{}{"components": components}
+	switch {
+	case time.Since(ctx.Id.Time()) < tokenLifetime-30*time.Second:
+		// the interaction token can edit ephemeral messages too
+		target := "@original"
+		if !original && messageId != "" {
+			target = string(messageId)
+		}
+		path := fmt.Sprintf("/webhooks/%s/%s/messages/%s", ctx.ApplicationId, ctx.Token, target)
+		MinimalReq("PATCH", path, body, "").Request()
+	case messageId != "" && !ephemeral && ctx.bot != nil:
+		path := fmt.Sprintf("/channels/%s/messages/%s", channelId, messageId)
+		MinimalReq("PATCH", path, body, ctx.bot.token).Request()
+	}
+}
+
+func isEphemeral(message map[string]interface{}) bool {
+	flags, _ := message["flags"].(float64)
+	return int(flags)&(1<<6) != 0
+}
+
+func disableComponents(components []interface{}) []interface{} {
+	var disabled []interface{}
+	for _, c := range components {
+		comp, ok := c.(map[string]interface{})
+		if !ok {
+			disabled = append(disabled, c)
+			continue
+		}
+		cp := map[string]interface{}{}
+		for k, v := range comp {
+			cp[k] = v
+		}
+		if children, ok := comp["components"].([]interface{}); ok {
+			cp["components"] = disableComponents(children)
+		} else if cp["type"] != 1 {
+			cp["disabled"] = true
+		}
+		disabled = append(disabled, cp)
+	}
+	return disabled
+}
+
+// Stop ends the view early, its callbacks stop responding;
+// the view must have been created with NewView
+func (v *View) Stop() {
+	if v.state == nil {
+		panic("View {stop} requires a view created with NewView")
+	}
+	go v.state.finish(false)
+}
+
+// track attaches a sent view to its interaction and message
+func (v *View) track(ctx Context, channelId Snowflake, messageId Snowflake, ephemeral bool) {
+	if v.state != nil && len(v.ActionRows) > 0 {
+		v.state.track(ctx, channelId, messageId, ephemeral)
+	}
+}